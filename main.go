@@ -55,6 +55,34 @@ func run(_configFile, _logPath string) {
 	if err != nil {
 		panic(err)
 	}
+
+	// 更新闰秒表
+	if settings.LeapSecondFile != "" {
+		if err := astro.LoadLeapSeconds(settings.LeapSecondFile); err != nil {
+			panic(err.Error())
+		}
+	}
+	// ΔT的模型
+	if settings.DeltaTFile != "" {
+		if _, err := astro.LoadObservedDeltaTModel("observed", settings.DeltaTFile); err != nil {
+			panic(err.Error())
+		}
+	}
+	if settings.DeltaTModel != "" {
+		model, err := astro.GetDeltaTModel(settings.DeltaTModel)
+		if err != nil {
+			panic(err.Error())
+		}
+		astro.DefaultDeltaTModel = model
+	}
+	// 默认的改历
+	if settings.CalendarReform != "" {
+		reform, err := astro.GetCalendarReform(settings.CalendarReform)
+		if err != nil {
+			panic(err.Error())
+		}
+		astro.DefaultCalendarReform = reform
+	}
 	exec, _ := task_pool.NewExecutor(task_pool.DefaultExecutorParams(), l.Sugar())
 	defer exec.Stop()
 	exec.ListenStopSignal()
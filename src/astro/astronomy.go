@@ -3,6 +3,7 @@ package astro
 import (
 	"go-swe/src/swe"
 	"math"
+	"time"
 )
 
 const (
@@ -14,8 +15,8 @@ const (
 	SinSolarParallax           = EquatorialRadius / AU                                                    // sin(太阳视差)
 	LightVelocity              = 299792.458                                                               // 光速(行米/秒)
 	LightTimePerAU             = AU / LightVelocity / 86400 / 36525                                       // 每天文单位的光行时间(儒略世纪)
-	LunarEarthRatio            = 0.2725076                                                                // 月亮与地球的半径比(用于半影计算)
-	LunarEarthRatio2           = 0.2722810                                                                // 月亮与地球的半径比(用于本影计算)
+	LunarEarthRatio            = 0.2725076                                                                // 月亮与地球的半径比(用于半影计算, 目前只用于月亮视半径, 月食的半影由swe计算)
+	LunarEarthRatio2           = 0.2722810                                                                // 月亮与地球的半径比(用于本影计算, 目前只用于月亮视半径, 月食的本影由swe计算)
 	SolarEarthRatio            = 109.1222                                                                 // 太阳与地球的半径比(对应959.64)
 	ApparentLunarRadius        = LunarEarthRatio * EquatorialRadius * 1.0000036 * DegreeSecondsPerRadian  // 用于月亮视半径计算
 	ApparentLunarRadius2       = LunarEarthRatio2 * EquatorialRadius * 1.0000036 * DegreeSecondsPerRadian // 用于月亮视半径计算
@@ -31,6 +32,10 @@ var PlanetaryRendezvousPeriod = [...]float64{116, 584, 780, 399, 378, 370, 367,
 type Astronomy struct {
 	// Swe的实例
 	Swe swe.SweInterface
+	// ΔT的模型, 为nil时使用 DefaultDeltaTModel
+	DeltaTModel DeltaTModel
+	// 按年计算时年初、年末所用的改历, 为nil时使用 DefaultCalendarReform
+	CalendarReform *CalendarReform
 }
 
 type EclipticProperties struct {
@@ -86,8 +91,49 @@ func (astro *Astronomy) DeltaTEx(jdUT JulianDay) float64 {
 	return deltaT
 }
 
+// DeltaT 使用当前的ΔT模型计算 TT - UT1 单位是 天
 func (astro *Astronomy) DeltaT(jdUT JulianDay) float64 {
-	return astro.Swe.DeltaT(float64(jdUT))
+	return astro.deltaTModel().DeltaT(jdUT)
+}
+
+func (astro *Astronomy) deltaTModel() DeltaTModel {
+	if astro.DeltaTModel == nil {
+		return DefaultDeltaTModel
+	}
+	return astro.DeltaTModel
+}
+
+// WithDeltaTModel 返回使用指定ΔT模型的 Astronomy, 与原实例共享Swe
+//	model ΔT的模型, 为nil时使用 DefaultDeltaTModel
+func (astro *Astronomy) WithDeltaTModel(model DeltaTModel) *Astronomy {
+	_astro := *astro
+	_astro.DeltaTModel = model
+	return &_astro
+}
+
+func (astro *Astronomy) calendarReform() *CalendarReform {
+	if astro.CalendarReform == nil {
+		return DefaultCalendarReform
+	}
+	return astro.CalendarReform
+}
+
+// TimeToJulianDay 同 TimeToJulianDay, 但按照当前的改历, 见 CalendarReform.TimeToJulianDay
+func (astro *Astronomy) TimeToJulianDay(t time.Time) JulianDay {
+	return astro.calendarReform().TimeToJulianDay(t)
+}
+
+// WithCalendarReform 返回使用指定改历的 Astronomy, 与原实例共享Swe
+//	reform 改历, 为nil时使用 DefaultCalendarReform
+func (astro *Astronomy) WithCalendarReform(reform *CalendarReform) *Astronomy {
+	_astro := *astro
+	_astro.CalendarReform = reform
+	return &_astro
+}
+
+// NewEphemerisTime 同 NewEphemerisTime, 但使用当前的ΔT模型
+func (astro *Astronomy) NewEphemerisTime(jdUT JulianDay) *EphemerisTime {
+	return &EphemerisTime{JdUT: jdUT, DeltaT: astro.DeltaT(jdUT)}
 }
 
 func (astro *Astronomy) simpleCalcFlags(deltaT float64) *swe.CalcFlags {
@@ -164,8 +210,23 @@ func (astro *Astronomy) PlanetPropertiesWithObserver(
 		planet.Ecliptic.Longitude -= 20.5 / DegreeSecondsPerRadian
 	}
 
+	planet.HourAngle, planet.Equatorial, planet.Horizontal = astro.observerCoordinates(planet.Ecliptic, planet.Distance, ecliptic, jdET, geo, withRevise)
+
+	return
+}
+
+// observerCoordinates 黄道坐标转为观察者的时角、赤道坐标、地平坐标
+//	distance 天体的距离(AU), 用于视差的修正
+//	ecliptic 当前黄道倾角、章动等参数
+func (astro *Astronomy) observerCoordinates(
+	eclipticCoordinates *EclipticCoordinates,
+	distance float64,
+	ecliptic *EclipticProperties,
+	jdET *EphemerisTime,
+	geo *GeographicCoordinates,
+	withRevise bool) (HourAngle, *EquatorialCoordinates, *HorizontalCoordinates) {
 	// 黄道坐标 -> 赤道坐标
-	equatorial := EclipticToEquatorial(planet.Ecliptic, IfThenElse(withRevise, ecliptic.TrueObliquity, ecliptic.MeanObliquity).(float64))
+	equatorial := EclipticToEquatorial(eclipticCoordinates, IfThenElse(withRevise, ecliptic.TrueObliquity, ecliptic.MeanObliquity).(float64))
 
 	var sidTime float64
 	/* 快速计算sidreal time
@@ -210,15 +271,11 @@ func (astro *Astronomy) PlanetPropertiesWithObserver(
 			_horizontal.Latitude += AstronomicalRefraction2(_horizontal.Latitude)
 		}
 		// 直接在地平坐标中视差修正(这里把地球看为球形,精度比 Parallax 秒差一些)
-		_horizontal.Latitude -= 8.794 / DegreeSecondsPerRadian / planet.Distance * math.Cos(_horizontal.Latitude)
+		_horizontal.Latitude -= 8.794 / DegreeSecondsPerRadian / distance * math.Cos(_horizontal.Latitude)
 		horizontal = _horizontal.AsHorizontalCoordinates()
 	} else {
 		horizontal = EquatorialToHorizontal(hourAngle, equatorial.Declination, geo.Latitude)
 	}
 
-	planet.HourAngle = hourAngle
-	planet.Equatorial = equatorial
-	planet.Horizontal = horizontal
-
-	return
+	return hourAngle, equatorial, horizontal
 }
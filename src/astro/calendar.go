@@ -3,8 +3,54 @@ package astro
 import (
 	"fmt"
 	"math"
+	"time"
 )
 
+// LunarCalendar 阴阳历, 规则都与农历相同(定朔, 定气, 无中气置闰), 只是计算日期的子午线(时区)不同
+type LunarCalendar struct {
+	// 名称
+	Name string `json:"name"`
+	// 时区的偏移(日), 比如东八区是 8/24
+	Offset float64 `json:"offset"`
+	// 该历法的节日
+	Festivals []*FestivalRule `json:"-"`
+}
+
+var (
+	// ChineseCalendar 农历, 东八区
+	ChineseCalendar = &LunarCalendar{Name: "chinese", Offset: JD_CST_OFFSET, Festivals: ChineseFestivals}
+	// VietnameseCalendar 越南阴历, 东七区
+	VietnameseCalendar = &LunarCalendar{Name: "vietnamese", Offset: 7. / 24., Festivals: VietnameseFestivals}
+	// KoreanCalendar 韩国阴历, 东九区
+	KoreanCalendar = &LunarCalendar{Name: "korean", Offset: 9. / 24., Festivals: KoreanFestivals}
+	// JapaneseCalendar 日本旧历(天保历), 京都的地方平时 东经135.77°
+	JapaneseCalendar = &LunarCalendar{Name: "japanese", Offset: 135.77 / 360.}
+)
+
+// LunarCalendars 所有预设的阴阳历
+var LunarCalendars = map[string]*LunarCalendar{
+	ChineseCalendar.Name:    ChineseCalendar,
+	VietnameseCalendar.Name: VietnameseCalendar,
+	KoreanCalendar.Name:     KoreanCalendar,
+	JapaneseCalendar.Name:   JapaneseCalendar,
+}
+
+// GetLunarCalendar 根据名称获取预设的阴阳历, 名称为空时返回农历
+func GetLunarCalendar(name string) (*LunarCalendar, error) {
+	if name == "" {
+		return ChineseCalendar, nil
+	}
+	if calendar, ok := LunarCalendars[name]; ok {
+		return calendar, nil
+	}
+	return nil, fmt.Errorf("unknown lunar calendar: %s", name)
+}
+
+// Location 该历法的时区
+func (cal *LunarCalendar) Location() *time.Location {
+	return time.FixedZone(cal.Name, int(math.Round(cal.Offset*86400)))
+}
+
 type LunarMonth struct {
 	// 朔时间
 	JdUT JulianDay `json:"jd_ut"`
@@ -21,18 +67,30 @@ type LunarMonth struct {
 // 采用的标准的天文计算的：定朔, 定气法
 // 夏正（建寅、寅正）：以冬至日必须在子月（寅正十一月），上个冬至月（寅正十一月）到下个冬至月如有12个月就不置闰，如有13个月就要置闰，以上个冬至月之后第一个无中气的月份为闰月
 func (astro *Astronomy) LunarMonths(year int) ([]*LunarMonth, error) {
+	return astro.LunarMonthsWithCalendar(year, ChineseCalendar)
+}
+
+// LunarMonthsWithCalendar 按照某个阴阳历的时区计算 LunarMonths
+//	year 年
+//	cal 阴阳历, 比如 VietnameseCalendar
+func (astro *Astronomy) LunarMonthsWithCalendar(year int, cal *LunarCalendar) ([]*LunarMonth, error) {
 	// 去年/今年冬至日
-	winterSolstices, err := astro.SolarEclipticLongitudesToTimes(DateToJulianDay(year-1, 12, 1, 0, 0, 0), []float64{ToRadians(270), ToRadians(270)})
+	winterSolstices, err := astro.SolarEclipticLongitudesToTimes(astro.calendarReform().DateToJulianDay(year-1, 12, 1, 0, 0, 0), []float64{ToRadians(270), ToRadians(270)})
 	if err != nil {
 		return nil, fmt.Errorf("LunarMonths WinterSolstices: %w", err)
 	}
+	// 冬至必须在十一月, 按照该历法时区的日期比较: 冬至当日(含)之前的最后一个朔日
+	// 朔与冬至在当地的同一天时, 即使朔晚于冬至, 该朔所在的月也包含冬至日
+	var lastNewMoonOfDay = func(jd JulianDay) (JulianDay, error) {
+		return astro.LastNewMoons(jd.ToLocation(cal.Offset).EndOfDay().ToJulianDay(cal.Offset))
+	}
 	// 去年冬至之前的第一个朔日
-	lastNewMoon, err := astro.LastNewMoons(winterSolstices[0])
+	lastNewMoon, err := lastNewMoonOfDay(winterSolstices[0])
 	if err != nil {
 		return nil, fmt.Errorf("LunarMonths LastNewMoon: %w", err)
 	}
 	// 今年冬至日的第一个朔日
-	nextNewMoon, err := astro.LastNewMoons(winterSolstices[1])
+	nextNewMoon, err := lastNewMoonOfDay(winterSolstices[1])
 	if err != nil {
 		return nil, fmt.Errorf("LunarMonths NextNewMoon: %w", err)
 	}
@@ -51,12 +109,12 @@ func (astro *Astronomy) LunarMonths(year int) ([]*LunarMonth, error) {
 	has13 := FloatEqual(float64(newMoons[len(newMoons)-1]), float64(nextNewMoon), 9)
 
 	// 是否有中气
-	// 也需要按照东八区计算
+	// 也需要按照该历法的时区计算
 	var hasMiddleChi = func(start, end JulianDayWithLocation) bool {
 
 		for _, jdExtra := range solarTerms {
-			cst := jdExtra.JdUT.ToCST()
-			if cst >= start && cst < end {
+			jdz := jdExtra.JdUT.ToLocation(cal.Offset)
+			if jdz >= start && jdz < end {
 				// 雨水、春分、谷雨、小满、夏至、大暑、处暑、秋分、霜降、小雪、冬至和大寒
 				// 也就是可以整除2的是中气
 				if jdExtra.Index%2 == 0 {
@@ -73,9 +131,9 @@ func (astro *Astronomy) LunarMonths(year int) ([]*LunarMonth, error) {
 		var newMoon = newMoons[i]
 		var nextNewMoon = newMoons[i+1]
 
-		// 转化成东八区的0点
-		var start = newMoon.ToCST().StartOfDay()
-		var end = nextNewMoon.ToCST().StartOfDay()
+		// 转化成该历法时区的0点
+		var start = newMoon.ToLocation(cal.Offset).StartOfDay()
+		var end = nextNewMoon.ToLocation(cal.Offset).StartOfDay()
 
 		// 计算是第几月 子月是11月
 		var index = 10 + i
@@ -83,7 +141,7 @@ func (astro *Astronomy) LunarMonths(year int) ([]*LunarMonth, error) {
 		if has13 && leapMonth == math.MaxInt8 && !hasMiddleChi(start, end) {
 			leapMonth = index
 		}
-		// 该月有多少天，按照东八区0点计算
+		// 该月有多少天，按照该历法时区的0点计算
 		var days = int(end - start)
 
 		months[i] = &LunarMonth{
@@ -97,14 +155,235 @@ func (astro *Astronomy) LunarMonths(year int) ([]*LunarMonth, error) {
 	return months, nil
 }
 
+// LunarDate 农历日期
+type LunarDate struct {
+	// 农历年, 以正月初一为岁首
+	Year int `json:"year"`
+	// 月, 1~12
+	Month int `json:"month"`
+	// 是否是闰月
+	Leap bool `json:"leap"`
+	// 日, 1~30
+	Day int `json:"day"`
+	// 该月有多少天
+	MonthDays int `json:"month_days"`
+	// 月的名称, 如: 闰六月
+	MonthString string `json:"month_string"`
+	// 日的名称, 如: 初八
+	DayString string `json:"day_string"`
+	// 该日当地(该历法的时区)0点的儒略日(UT)
+	JdUT JulianDay `json:"jd_ut"`
+}
+
+// lunarYearMonth 带农历年的月, 以及该历法时区0点的起始时间
+type lunarYearMonth struct {
+	*LunarMonth
+	Year   int
+	Start  JulianDayWithLocation
+	Offset float64
+}
+
+// lunarYearMonths LunarMonths(year)和LunarMonths(year+1)的所有月, 并标注所属的农历年
+// LunarMonths(year)从year-1年的冬至月(十一月)开始, 所以农历year年的正月至腊月跨越了这两段
+func (astro *Astronomy) lunarYearMonths(year int, cal *LunarCalendar) ([]*lunarYearMonth, error) {
+	return astro.lunarYearMonthsRange(year, year+1, cal)
+}
+
+// lunarYearMonthsRange LunarMonths(fromYear) ~ LunarMonths(toYear)的所有月, 并标注所属的农历年
+func (astro *Astronomy) lunarYearMonthsRange(fromYear, toYear int, cal *LunarCalendar) ([]*lunarYearMonth, error) {
+	var months []*lunarYearMonth
+	for y := fromYear; y <= toYear; y++ {
+		lunarMonths, err := astro.LunarMonthsWithCalendar(y, cal)
+		if err != nil {
+			return nil, err
+		}
+
+		// 正月之前的月属于上一个农历年
+		lunarYear := y - 1
+		for _, month := range lunarMonths {
+			if month.Index == 0 && !month.Leap {
+				lunarYear = y
+			}
+			months = append(months, &lunarYearMonth{
+				LunarMonth: month,
+				Year:       lunarYear,
+				Start:      month.JdUT.ToLocation(cal.Offset).StartOfDay(),
+				Offset:     cal.Offset,
+			})
+		}
+	}
+	return months, nil
+}
+
+func newLunarDate(month *lunarYearMonth, day int) *LunarDate {
+	return &LunarDate{
+		Year:        month.Year,
+		Month:       month.Index + 1,
+		Leap:        month.Leap,
+		Day:         day,
+		MonthDays:   month.Days,
+		MonthString: GetLunarMonthString(month.Index, month.Leap),
+		DayString:   LunarDayStrings[day-1],
+		JdUT:        month.Start.AddDays(day - 1).ToJulianDay(month.Offset),
+	}
+}
+
+// SolarToLunar 公历转农历
+//	t 公历日期, 取t所在时区的年月日, 按照东八区计算
+func (astro *Astronomy) SolarToLunar(t time.Time) (*LunarDate, error) {
+	return astro.SolarToLunarWithCalendar(t, ChineseCalendar)
+}
+
+// SolarToLunarWithCalendar 公历转某个阴阳历
+//	t 公历日期, 取t所在时区的年月日, 按照该历法的时区计算
+//	cal 阴阳历
+func (astro *Astronomy) SolarToLunarWithCalendar(t time.Time, cal *LunarCalendar) (*LunarDate, error) {
+	jdz := JulianDayWithLocation(astro.calendarReform().DateToJulianDay(t.Year(), int(t.Month()), t.Day(), 0, 0, 0))
+
+	months, err := astro.lunarYearMonths(t.Year(), cal)
+	if err != nil {
+		return nil, fmt.Errorf("SolarToLunar LunarMonths: %w", err)
+	}
+
+	for _, month := range months {
+		if jdz >= month.Start && jdz < month.Start.AddDays(month.Days) {
+			return newLunarDate(month, int(math.Round(float64(jdz-month.Start)))+1), nil
+		}
+	}
+
+	// 原则上不可能出现这种错误
+	return nil, fmt.Errorf("SolarToLunar: %s out of range", t.Format("2006-01-02"))
+}
+
+// LunarToSolar 农历转公历
+//	lunarYear 农历年
+//	month 月, 1~12
+//	day 日, 1~30
+//	leap 是否是闰月
+func (astro *Astronomy) LunarToSolar(lunarYear, month, day int, leap bool) (*LunarDate, error) {
+	return astro.LunarToSolarWithCalendar(lunarYear, month, day, leap, ChineseCalendar)
+}
+
+// LunarToSolarWithCalendar 某个阴阳历转公历, 参数见 LunarToSolar
+//	cal 阴阳历
+func (astro *Astronomy) LunarToSolarWithCalendar(lunarYear, month, day int, leap bool, cal *LunarCalendar) (*LunarDate, error) {
+	if month < 1 || month > 12 {
+		return nil, fmt.Errorf("LunarToSolar: invalid month %d", month)
+	}
+
+	months, err := astro.lunarYearMonths(lunarYear, cal)
+	if err != nil {
+		return nil, fmt.Errorf("LunarToSolar LunarMonths: %w", err)
+	}
+
+	for _, m := range months {
+		if m.Year != lunarYear || m.Index != month-1 || m.Leap != leap {
+			continue
+		}
+		if day < 1 || day > m.Days {
+			return nil, fmt.Errorf("LunarToSolar: %d%s has no day %d", lunarYear, GetLunarMonthString(m.Index, m.Leap), day)
+		}
+		return newLunarDate(m, day), nil
+	}
+
+	return nil, fmt.Errorf("LunarToSolar: %d has no %s", lunarYear, GetLunarMonthString(month-1, leap))
+}
+
+// DayPeriod 按照东八区的自然日计算的一段日子
+type DayPeriod struct {
+	// 第一天东八区0点的儒略日(UT)
+	Start JulianDay `json:"start"`
+	// 最后一天的次日东八区0点的儒略日(UT), 不含
+	End JulianDay `json:"end"`
+	// 共多少天
+	Days int `json:"days"`
+}
+
+// DogDays 三伏
+type DogDays struct {
+	// 初伏
+	Initial *DayPeriod `json:"initial"`
+	// 中伏
+	Middle *DayPeriod `json:"middle"`
+	// 末伏
+	Last *DayPeriod `json:"last"`
+}
+
+func newDayPeriod(start JulianDayWithLocation, days int) *DayPeriod {
+	return &DayPeriod{
+		Start: start.ToJulianDay(JD_CST_OFFSET),
+		End:   start.AddDays(days).ToJulianDay(JD_CST_OFFSET),
+		Days:  days,
+	}
+}
+
+// nextStemDay jdz当天或之后的第一个天干为stem的日子(东八区0点)
+func nextStemDay(jdz JulianDayWithLocation, stem int) JulianDayWithLocation {
+	jdz = jdz.StartOfDay()
+	return jdz.AddDays(intMod(stem-DayGanZhi(jdz).Stem, 10))
+}
+
+// solarTermOfYear 某年的某个节气
+func (astro *Astronomy) solarTermOfYear(year, index int) (JulianDay, error) {
+	reform := astro.calendarReform()
+	jd := reform.DateToJulianDay(year, 1, 1, 0, 0, 0)
+	solarTerms, err := astro.SolarTermsRange(jd, reform.AddYears(jd, 1))
+	if err != nil {
+		return 0, err
+	}
+	for _, solarTerm := range solarTerms {
+		if solarTerm.Index == index {
+			return solarTerm.JdUT, nil
+		}
+	}
+	// 原则上不可能出现这种错误
+	return 0, fmt.Errorf("%d has no %s", year, SolarTermsString[index])
+}
+
 // DogDays 伏天
 // 从夏至开始，依照干支纪日的排列，第3个庚日为初伏，第4个庚日为中伏，立秋后第1个庚日为末伏。当夏至与立秋之间出现4个庚日时中伏为10天，出现5个庚日则为20天
-func (astro *Astronomy) DogDays(year int) {
+// 夏至、立秋当日为庚日的, 也计算在内
+func (astro *Astronomy) DogDays(year int) (*DogDays, error) {
+	// 庚
+	const stem = 6
 
+	summerSolstice, err := astro.solarTermOfYear(year, 6)
+	if err != nil {
+		return nil, fmt.Errorf("DogDays SummerSolstice: %w", err)
+	}
+	autumnBegins, err := astro.solarTermOfYear(year, 9)
+	if err != nil {
+		return nil, fmt.Errorf("DogDays AutumnBegins: %w", err)
+	}
+
+	// 夏至后第3个庚日
+	initial := nextStemDay(summerSolstice.ToCST(), stem).AddDays(20)
+	// 第4个庚日
+	middle := initial.AddDays(10)
+	// 立秋后第1个庚日
+	last := nextStemDay(autumnBegins.ToCST(), stem)
+
+	return &DogDays{
+		Initial: newDayPeriod(initial, 10),
+		Middle:  newDayPeriod(middle, int(math.Round(float64(last-middle)))),
+		Last:    newDayPeriod(last, 10),
+	}, nil
 }
 
-// Winter9Days 九天
-//冬至逢壬日为起点，每“九天”算一“九”，
-func (astro *Astronomy) Winter9Days(year int) {
+// Winter9Days 数九
+// 从冬至日起，每九天算一“九”，共九九八十一天
+//	year 年, 即该年12月的冬至
+func (astro *Astronomy) Winter9Days(year int) ([]*DayPeriod, error) {
+	winterSolstice, err := astro.solarTermOfYear(year, 18)
+	if err != nil {
+		return nil, fmt.Errorf("Winter9Days WinterSolstice: %w", err)
+	}
+
+	start := winterSolstice.ToCST().StartOfDay()
+	periods := make([]*DayPeriod, 9)
+	for i := range periods {
+		periods[i] = newDayPeriod(start.AddDays(i*9), 9)
+	}
 
+	return periods, nil
 }
@@ -175,18 +175,31 @@ func EquatorialToHorizontal(hourAngle HourAngle, declination, latitude float64)
 //	declination: 赤纬
 //	latitude: 观察者纬度
 //	altitude: 高度角
+//
+// 如果天体全天都在该高度角之上或之下(比如极昼、极夜), 时角会被限制在0°或180°, 需要判断状态请使用 altitudeToHourAngle
 func AltitudeToHourAngle(declination, latitude, altitude float64) HourAngle {
+	ha, _ := altitudeToHourAngle(declination, latitude, altitude)
+	return ha
+}
+
+// altitudeToHourAngle 同 AltitudeToHourAngle, 并且返回天体是否全天都在该高度角之上或之下
+func altitudeToHourAngle(declination, latitude, altitude float64) (HourAngle, TwilightState) {
 	// 纬度用φ表示，赤纬用δ表示，地方时(时角)以H表示：
 	// sin(Alt) = sin(φ) * sin(δ) + cos(φ) * cos(δ) * cos(H)
 	ha := (math.Sin(altitude) - math.Sin(latitude)*math.Sin(declination)) / math.Cos(declination) / math.Cos(latitude)
 
+	state := TwilightNormal
 	// > 180°
 	if ha > 1 {
+		// 上中天也达不到该高度角
 		ha = 1
+		state = TwilightAlwaysBelow
 	} else if ha < -1 {
+		// 下中天也在该高度角之上
 		ha = -1
+		state = TwilightAlwaysAbove
 	}
-	return HourAngle(math.Acos(ha))
+	return HourAngle(math.Acos(ha)), state
 }
 
 // EclipticEquatorialConverter 黄道坐标 <-> 赤道坐标 互转 也就是 球面坐标旋转
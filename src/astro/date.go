@@ -0,0 +1,247 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+	"math"
+	"time"
+)
+
+// CalendarReform 儒略历改为格里高利历的改历, 首个格里高利历日期(含)之后使用格里高利历, 之前使用儒略历
+// 本包的年份均为天文纪年: 公元前1年为0年, 公元前2年为-1年, 以此类推, 同 time.Time
+type CalendarReform struct {
+	Name string `json:"name"`
+	// 首个格里高利历日期, 比如: 1582-10-15
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+	// 首个格里高利历日期0时的儒略日, ProlepticGregorian 为 -Inf, ProlepticJulian 为 +Inf
+	JdUT JulianDay `json:"-"`
+}
+
+// CalendarDate 历法日期, 改历之前为儒略历, 年份为天文纪年
+type CalendarDate struct {
+	Year   int     `json:"year"`
+	Month  int     `json:"month"`
+	Day    int     `json:"day"`
+	Hour   int     `json:"hour"`
+	Minute int     `json:"minute"`
+	Second float64 `json:"second"`
+	// 时区的偏移 单位是 秒
+	Offset int `json:"offset"`
+	// swe.Gregorian 或 swe.Julian
+	Calendar swe.CalType `json:"calendar"`
+}
+
+// NewCalendarReform 按照首个格里高利历日期创建改历
+func NewCalendarReform(name string, year, month, day int) *CalendarReform {
+	jd, _ := swe.NewSwe().JulDay(year, month, day, 0, swe.Gregorian)
+	return &CalendarReform{Name: name, Year: year, Month: month, Day: day, JdUT: JulianDay(jd)}
+}
+
+var (
+	// GregorianReform 教皇格里高利十三世的改历, 1582-10-04(儒略历)的次日为1582-10-15, 意大利、西班牙、葡萄牙、波兰等
+	GregorianReform = NewCalendarReform("gregorian", 1582, 10, 15)
+	// FranceReform 法国, 1582-12-09 的次日为 1582-12-20
+	FranceReform = NewCalendarReform("france", 1582, 12, 20)
+	// PrussiaReform 普鲁士, 1610-08-22 的次日为 1610-09-02
+	PrussiaReform = NewCalendarReform("prussia", 1610, 9, 2)
+	// BritainReform 英国及其殖民地(含北美), 1752-09-02 的次日为 1752-09-14
+	BritainReform = NewCalendarReform("britain", 1752, 9, 14)
+	// SwedenReform 瑞典, 1753-02-17 的次日为 1753-03-01
+	SwedenReform = NewCalendarReform("sweden", 1753, 3, 1)
+	// RussiaReform 俄国, 1918-01-31 的次日为 1918-02-14
+	RussiaReform = NewCalendarReform("russia", 1918, 2, 14)
+	// GreeceReform 希腊, 1923-02-15 的次日为 1923-03-01
+	GreeceReform = NewCalendarReform("greece", 1923, 3, 1)
+	// ProlepticGregorian 预推格里高利历, 所有日期都使用格里高利历, 同 time.Time
+	ProlepticGregorian = &CalendarReform{Name: "proleptic_gregorian", JdUT: JulianDay(math.Inf(-1))}
+	// ProlepticJulian 预推儒略历, 所有日期都使用儒略历
+	ProlepticJulian = &CalendarReform{Name: "proleptic_julian", JdUT: JulianDay(math.Inf(1))}
+)
+
+// CalendarReforms 所有预设的改历
+var CalendarReforms = map[string]*CalendarReform{
+	GregorianReform.Name:    GregorianReform,
+	FranceReform.Name:       FranceReform,
+	PrussiaReform.Name:      PrussiaReform,
+	BritainReform.Name:      BritainReform,
+	SwedenReform.Name:       SwedenReform,
+	RussiaReform.Name:       RussiaReform,
+	GreeceReform.Name:       GreeceReform,
+	ProlepticGregorian.Name: ProlepticGregorian,
+	ProlepticJulian.Name:    ProlepticJulian,
+}
+
+// DefaultCalendarReform 未指定改历时使用的改历, 比如 JulianDay.ToDate
+var DefaultCalendarReform = GregorianReform
+
+// GetCalendarReform 根据名称获取预设的改历, 名称为空时返回 DefaultCalendarReform
+func GetCalendarReform(name string) (*CalendarReform, error) {
+	if name == "" {
+		return DefaultCalendarReform, nil
+	}
+	if reform, ok := CalendarReforms[name]; ok {
+		return reform, nil
+	}
+	return nil, fmt.Errorf("unknown calendar reform: %s", name)
+}
+
+// CalType 儒略日(UT)所在的日期使用的历法
+func (reform *CalendarReform) CalType(jd JulianDay) swe.CalType {
+	if jd >= reform.JdUT {
+		return swe.Gregorian
+	}
+	return swe.Julian
+}
+
+// CalTypeOfDate 日期使用的历法
+// 改历时被跳过的日期(比如 1582-10-05 ~ 1582-10-14)视为儒略历
+func (reform *CalendarReform) CalTypeOfDate(year, month, day int) swe.CalType {
+	switch {
+	case math.IsInf(float64(reform.JdUT), -1):
+		return swe.Gregorian
+	case math.IsInf(float64(reform.JdUT), 1):
+		return swe.Julian
+	}
+
+	// 月、日均小于100, 组合后的数字与日期的顺序一致
+	date := year*10000 + month*100 + day
+	if date >= reform.Year*10000+reform.Month*100+reform.Day {
+		return swe.Gregorian
+	}
+	return swe.Julian
+}
+
+// DateToJulianDay 年,月,日,时,分,秒 (只能为UTC) 转换为 儒略日 JulianDay, 按照日期选择历法
+func (reform *CalendarReform) DateToJulianDay(year, month, day, hour, minute int, second float64) JulianDay {
+	jd, _ := swe.NewSwe().JulDay(year, month, day, MakeJulianDayHours(hour, minute, second), reform.CalTypeOfDate(year, month, day))
+	return JulianDay(jd)
+}
+
+// ExtractJulianDay 儒略日 JulianDay 转化为 年,月,日,时,分,秒 (UTC), 以及使用的历法
+func (reform *CalendarReform) ExtractJulianDay(jd JulianDay) (year, month, day, hour, minute int, second float64, calType swe.CalType) {
+	calType = reform.CalType(jd)
+	year, month, day, hours, _ := swe.NewSwe().RevJul(float64(jd), calType)
+	hour, minute, second = ExtractJulianDayHours(hours)
+	return
+}
+
+// TimeToJulianDay 将 time 的年月日时分秒(含时区)视为该改历下的日期, 转为 儒略日 JulianDay
+// time.Time 本身是预推格里高利历, 所以改历之前的日期与 TimeToJulianDay 的结果不同
+func (reform *CalendarReform) TimeToJulianDay(_time time.Time) JulianDay {
+	_, offset := _time.Zone()
+	second := float64(_time.Second()) + float64(_time.Nanosecond())/1e9
+	jd := reform.DateToJulianDay(_time.Year(), int(_time.Month()), _time.Day(), _time.Hour(), _time.Minute(), second)
+	return jd.Add(-float64(offset) / 86400.)
+}
+
+// AddYears 增加N年，减少用负数, 按照日期选择历法
+// 注意：大部分日历操作类都存在这个问题 2000-02-29 + 1 year -> 2001-03-01
+func (reform *CalendarReform) AddYears(jd JulianDay, years int) JulianDay {
+	y, m, d, t, _ := swe.NewSwe().RevJul(float64(jd), reform.CalType(jd))
+	_jd, _ := swe.NewSwe().JulDay(y+years, m, d, t, reform.CalTypeOfDate(y+years, m, d))
+	return JulianDay(_jd)
+}
+
+// AddMonths 增加N月，减少用负数, 按照日期选择历法
+// 注意：大部分日历操作类都存在这个问题 2000-03-31 - 1 month -> 2000-03-02
+func (reform *CalendarReform) AddMonths(jd JulianDay, months int) JulianDay {
+	y, m, d, t, _ := swe.NewSwe().RevJul(float64(jd), reform.CalType(jd))
+	// 月份规范到 1~12, swe_julday 不支持超出范围的月份
+	m += months - 1
+	y += int(math.Floor(float64(m) / 12))
+	m = m - int(math.Floor(float64(m)/12))*12 + 1
+	_jd, _ := swe.NewSwe().JulDay(y, m, d, t, reform.CalTypeOfDate(y, m, d))
+	return JulianDay(_jd)
+}
+
+// ToDate 儒略日转为local时区的历法日期, 改历之前为儒略历
+// 与 ToTime 不同, 可以表示改历之前的日期
+//	local 时区, 为nil时为UTC
+//	reform 改历, 为nil时为 DefaultCalendarReform
+func (jd JulianDay) ToDate(local *time.Location, reform *CalendarReform) *CalendarDate {
+	if reform == nil {
+		reform = DefaultCalendarReform
+	}
+
+	t := jd.toProlepticTime(local)
+	_, offset := t.Zone()
+	date := &CalendarDate{
+		Year:     t.Year(),
+		Month:    int(t.Month()),
+		Day:      t.Day(),
+		Hour:     t.Hour(),
+		Minute:   t.Minute(),
+		Second:   float64(t.Second()) + float64(t.Nanosecond())/1e9,
+		Offset:   offset,
+		Calendar: swe.Gregorian,
+	}
+
+	// 以当地日期的正午判断历法, 避免时区造成的日期边界问题, 时分秒与历法无关
+	noon, _ := swe.NewSwe().JulDay(date.Year, date.Month, date.Day, 12, swe.Gregorian)
+	if reform.CalType(JulianDay(noon)) == swe.Julian {
+		date.Year, date.Month, date.Day, _, _ = swe.NewSwe().RevJul(noon, swe.Julian)
+		date.Calendar = swe.Julian
+	}
+	return date
+}
+
+// toProlepticTime 儒略日转为local的 time.Time, 年月日为预推格里高利历
+func (jd JulianDay) toProlepticTime(local *time.Location) time.Time {
+	if local == nil {
+		local = time.UTC
+	}
+	year, month, day, hour, minute, second, _ := ProlepticGregorian.ExtractJulianDay(jd)
+	date := time.Date(year, time.Month(month), day, hour, minute, int(second), int((second-float64(int(second)))*1e9), time.UTC)
+	return date.In(local)
+}
+
+// Time 历法日期转为 time.Time, 年月日时分秒与历法日期相同, 时区为偏移固定的 time.FixedZone
+// 改历之前的日期, time.Time 表示的时刻与真实时刻相差数天, 只能用于读取年月日等字段
+// 注意: 儒略历特有的闰日(比如 1500-02-29) 会被 time.Date 规范为次日
+func (date *CalendarDate) Time() time.Time {
+	second := int(date.Second)
+	return time.Date(date.Year, time.Month(date.Month), date.Day, date.Hour, date.Minute, second, int((date.Second-float64(second))*1e9), time.FixedZone("", date.Offset))
+}
+
+// JulianDay 历法日期转为 儒略日 JulianDay (UT)
+func (date *CalendarDate) JulianDay() JulianDay {
+	jd, _ := swe.NewSwe().JulDay(date.Year, date.Month, date.Day, MakeJulianDayHours(date.Hour, date.Minute, date.Second), date.Calendar)
+	return JulianDay(jd).Add(-float64(date.Offset) / 86400.)
+}
+
+// HistoricalYear 历史纪年的年份, 没有0年, 公元前的年份为正数, 见 Era
+func (date *CalendarDate) HistoricalYear() int {
+	if date.Year <= 0 {
+		return 1 - date.Year
+	}
+	return date.Year
+}
+
+// Era 公元前为BCE, 公元为CE
+func (date *CalendarDate) Era() string {
+	return IfThenElse(date.Year <= 0, "BCE", "CE").(string)
+}
+
+// DateString 格式化为 YYYY-MM-DD, 年份为天文纪年, 负数年份比如 -0043-03-15
+func (date *CalendarDate) DateString() string {
+	year := fmt.Sprintf("%04d", date.Year)
+	if date.Year < 0 {
+		year = fmt.Sprintf("-%04d", -date.Year)
+	}
+	return fmt.Sprintf("%s-%02d-%02d", year, date.Month, date.Day)
+}
+
+// String 格式化为 YYYY-MM-DDThh:mm:ss±hh:mm, 改历之后的日期与 time.RFC3339 相同
+func (date *CalendarDate) String() string {
+	zone := "Z"
+	if date.Offset != 0 {
+		offset, sign := date.Offset, '+'
+		if offset < 0 {
+			offset, sign = -offset, '-'
+		}
+		zone = fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+	}
+	return fmt.Sprintf("%sT%02d:%02d:%02d%s", date.DateString(), date.Hour, date.Minute, int(date.Second), zone)
+}
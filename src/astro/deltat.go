@@ -0,0 +1,270 @@
+package astro
+
+import (
+	"bufio"
+	"fmt"
+	"go-swe/src/swe"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DeltaTModel ΔT(TT - UT1)的模型
+type DeltaTModel interface {
+	// Name 模型的名称, 用于 GetDeltaTModel
+	Name() string
+	// DeltaT 返回 TT - UT1 单位是 天
+	DeltaT(jdUT JulianDay) float64
+}
+
+// BuiltinDeltaTModel 内置的多项式表(dtAt), 即 DeltaT, 2015年之后为二次曲线外推, 近年的误差较大
+type BuiltinDeltaTModel struct{}
+
+// EspenakMeeus2006DeltaTModel Espenak & Meeus (2006) 的多项式, 即NASA日月食网站使用的ΔT
+type EspenakMeeus2006DeltaTModel struct{}
+
+// SweDeltaTModel 使用swe内置的ΔT模型, 见 swe.SweInterface.DeltaTModel
+type SweDeltaTModel struct {
+	ModelName string
+	Swe       swe.SweInterface
+	Model     swe.DeltaTModel
+	// 月球潮汐加速度, nil为根据星历表自动选择
+	TidAcc *float64
+}
+
+// ObservedDeltaTModel 用户提供的观测值表, 表内线性插值, 表外使用 Fallback
+type ObservedDeltaTModel struct {
+	ModelName string
+	// 年份(小数), 递增
+	Years []float64
+	// ΔT 单位是 秒
+	Values []float64
+	// 表外使用的模型
+	Fallback DeltaTModel
+}
+
+// SweDefaultDeltaTModel swe默认的ΔT, 即 swe.SweInterface.DeltaT
+var SweDefaultDeltaTModel = &SweDeltaTModel{ModelName: "swe", Swe: swe.NewSwe(), Model: swe.DeltaTDefault}
+
+// DefaultDeltaTModel 未指定模型时使用的ΔT模型, 比如 NewEphemerisTime、Astronomy.DeltaT
+var DefaultDeltaTModel DeltaTModel = SweDefaultDeltaTModel
+
+var deltaTModels = map[string]DeltaTModel{}
+var deltaTModelsLock sync.RWMutex
+
+func init() {
+	_swe := swe.NewSwe()
+	tidAcc := float64(swe.TidalStephenson2016)
+	RegisterDeltaTModel(BuiltinDeltaTModel{})
+	RegisterDeltaTModel(EspenakMeeus2006DeltaTModel{})
+	RegisterDeltaTModel(SweDefaultDeltaTModel)
+	RegisterDeltaTModel(&SweDeltaTModel{ModelName: "smh2016", Swe: _swe, Model: swe.DeltaTStephensonMorrisonHoh2016, TidAcc: &tidAcc})
+}
+
+// RegisterDeltaTModel 注册ΔT模型, 同名的会被覆盖
+func RegisterDeltaTModel(model DeltaTModel) {
+	deltaTModelsLock.Lock()
+	deltaTModels[model.Name()] = model
+	deltaTModelsLock.Unlock()
+}
+
+// GetDeltaTModel 根据名称获取ΔT模型, 名称为空时返回 DefaultDeltaTModel
+// 内置的模型有: builtin, em2006, swe, smh2016
+func GetDeltaTModel(name string) (DeltaTModel, error) {
+	if name == "" {
+		return DefaultDeltaTModel, nil
+	}
+
+	deltaTModelsLock.RLock()
+	defer deltaTModelsLock.RUnlock()
+	if model, ok := deltaTModels[name]; ok {
+		return model, nil
+	}
+	return nil, fmt.Errorf("GetDeltaTModel: unknown delta T model: %s", name)
+}
+
+// DeltaTModelNames 所有已注册的模型名称
+func DeltaTModelNames() []string {
+	deltaTModelsLock.RLock()
+	defer deltaTModelsLock.RUnlock()
+
+	names := make([]string, 0, len(deltaTModels))
+	for name := range deltaTModels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jdToDecimalYear 儒略日转为小数的年份
+func jdToDecimalYear(jdUT JulianDay) float64 {
+	return float64(jdUT.ToJD2000())/365.2425 + 2000
+}
+
+func (BuiltinDeltaTModel) Name() string {
+	return "builtin"
+}
+
+func (BuiltinDeltaTModel) DeltaT(jdUT JulianDay) float64 {
+	return DeltaT(jdUT)
+}
+
+func (EspenakMeeus2006DeltaTModel) Name() string {
+	return "em2006"
+}
+
+// DeltaT 分段多项式, 见 https://eclipse.gsfc.nasa.gov/SEhelp/deltatpoly2004.html
+func (EspenakMeeus2006DeltaTModel) DeltaT(jdUT JulianDay) float64 {
+	y := jdToDecimalYear(jdUT)
+
+	// 多项式求值, c[0] + c[1]*t + c[2]*t^2 + ...
+	poly := func(t float64, c ...float64) float64 {
+		v := 0.
+		for i := len(c) - 1; i >= 0; i-- {
+			v = v*t + c[i]
+		}
+		return v
+	}
+	ext := func(y float64) float64 {
+		u := (y - 1820) / 100
+		return -20 + 32*u*u
+	}
+
+	var dt float64
+	switch {
+	case y < -500:
+		dt = ext(y)
+	case y < 500:
+		dt = poly(y/100, 10583.6, -1014.41, 33.78311, -5.952053, -0.1798452, 0.022174192, 0.0090316521)
+	case y < 1600:
+		dt = poly((y-1000)/100, 1574.2, -556.01, 71.23472, 0.319781, -0.8503463, -0.005050998, 0.0083572073)
+	case y < 1700:
+		dt = poly(y-1600, 120, -0.9808, -0.01532, 1./7129)
+	case y < 1800:
+		dt = poly(y-1700, 8.83, 0.1603, -0.0059285, 0.00013336, -1./1174000)
+	case y < 1860:
+		dt = poly(y-1800, 13.72, -0.332447, 0.0068612, 0.0041116, -0.00037436, 0.0000121272, -0.0000001699, 0.000000000875)
+	case y < 1900:
+		dt = poly(y-1860, 7.62, 0.5737, -0.251754, 0.01680668, -0.0004473624, 1./233174)
+	case y < 1920:
+		dt = poly(y-1900, -2.79, 1.494119, -0.0598939, 0.0061966, -0.000197)
+	case y < 1941:
+		dt = poly(y-1920, 21.20, 0.84493, -0.076100, 0.0020936)
+	case y < 1961:
+		dt = poly(y-1950, 29.07, 0.407, -1./233, 1./2547)
+	case y < 1986:
+		dt = poly(y-1975, 45.45, 1.067, -1./260, -1./718)
+	case y < 2005:
+		dt = poly(y-2000, 63.86, 0.3345, -0.060374, 0.0017275, 0.000651814, 0.00002373599)
+	case y < 2050:
+		dt = poly(y-2000, 62.92, 0.32217, 0.005589)
+	case y < 2150:
+		dt = ext(y) - 0.5628*(2150-y)
+	default:
+		dt = ext(y)
+	}
+
+	return dt / 86400.
+}
+
+func (m *SweDeltaTModel) Name() string {
+	return m.ModelName
+}
+
+func (m *SweDeltaTModel) DeltaT(jdUT JulianDay) float64 {
+	// 默认的模型无需切换swe的全局设置
+	if m.Model == swe.DeltaTDefault && m.TidAcc == nil {
+		return m.Swe.DeltaT(float64(jdUT))
+	}
+	// 星历表文件缺失等错误时, swe仍会返回估算值, 所以忽略错误
+	dt, _ := m.Swe.DeltaTModel(float64(jdUT), &swe.DeltaTFlags{
+		Ephemeris: swe.FlagEphSwiss,
+		Model:     m.Model,
+		TidAcc:    m.TidAcc,
+	})
+	return dt
+}
+
+func (m *ObservedDeltaTModel) Name() string {
+	return m.ModelName
+}
+
+func (m *ObservedDeltaTModel) DeltaT(jdUT JulianDay) float64 {
+	y := jdToDecimalYear(jdUT)
+	n := len(m.Years)
+	if n == 0 || y < m.Years[0] || y > m.Years[n-1] {
+		fallback := m.Fallback
+		if fallback == nil {
+			fallback = EspenakMeeus2006DeltaTModel{}
+		}
+		return fallback.DeltaT(jdUT)
+	}
+
+	i := sort.SearchFloat64s(m.Years, y)
+	if i == 0 || m.Years[i] == y {
+		return m.Values[i] / 86400.
+	}
+	// 线性插值
+	t := (y - m.Years[i-1]) / (m.Years[i] - m.Years[i-1])
+	return (m.Values[i-1] + (m.Values[i]-m.Values[i-1])*t) / 86400.
+}
+
+// LoadObservedDeltaTModel 从文件中加载ΔT的观测值, 并注册为name
+// 文件格式同swe的 swe_deltat.txt: 每行为 年份 ΔT(秒), 比如: 2020.0 69.36, 以#开头的为注释
+// 表外使用 EspenakMeeus2006DeltaTModel
+//	name 模型名称
+//	path 文件路径
+func LoadObservedDeltaTModel(name, path string) (*ObservedDeltaTModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadObservedDeltaTModel: %w", err)
+	}
+	defer file.Close()
+
+	type point struct{ year, value float64 }
+	var points []point
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("LoadObservedDeltaTModel: invalid line %d: %s", line, text)
+		}
+		year, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("LoadObservedDeltaTModel: invalid year in line %d: %w", line, err)
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || math.IsNaN(value) {
+			return nil, fmt.Errorf("LoadObservedDeltaTModel: invalid delta T in line %d: %s", line, fields[1])
+		}
+		points = append(points, point{year, value})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("LoadObservedDeltaTModel: %w", err)
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("LoadObservedDeltaTModel: no delta T in %s", path)
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].year < points[j].year
+	})
+
+	model := &ObservedDeltaTModel{ModelName: name, Fallback: EspenakMeeus2006DeltaTModel{}}
+	for _, p := range points {
+		model.Years = append(model.Years, p.year)
+		model.Values = append(model.Values, p.value)
+	}
+
+	RegisterDeltaTModel(model)
+	return model, nil
+}
@@ -0,0 +1,381 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+)
+
+// 日食中心线的采样间隔: 5分钟
+const solarEclipseCentralLineStep = 5. / 60. / 24.
+
+// EclipsePathPoint 日食中心线上的一点
+type EclipsePathPoint struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 该时刻食甚所在的地理坐标
+	Geographic *GeographicCoordinates `json:"geographic"`
+}
+
+// SolarEclipse 日食(全球)
+type SolarEclipse struct {
+	// 日食类型, swe.EclTotal、swe.EclAnnular、swe.EclAnnularTotal、swe.EclPartial 之一,
+	// 并带有 swe.EclCentral 或 swe.EclNonCentral
+	Type swe.EclipseType `json:"type"`
+	// 食甚
+	Maximum JulianDay `json:"maximum"`
+	// 全球初亏 / 复圆, 即地球上任一处可见的偏食开始 / 结束
+	Begin JulianDay `json:"begin"`
+	End   JulianDay `json:"end"`
+	// 全球食既 / 生光, 即全食或环食的开始 / 结束, 偏食时为0
+	TotalityBegin JulianDay `json:"totality_begin"`
+	TotalityEnd   JulianDay `json:"totality_end"`
+	// 中心线的开始 / 结束, 非中心食时为0
+	CenterLineBegin JulianDay `json:"center_line_begin"`
+	CenterLineEnd   JulianDay `json:"center_line_end"`
+	// 食甚时刻, 地球上食分最大的地点
+	GreatestEclipse *GeographicCoordinates `json:"greatest_eclipse"`
+	// 食分(NASA的定义), 偏食为太阳直径被遮住的比例, 全食和环食为月日视直径之比
+	Magnitude float64 `json:"magnitude"`
+	// 月亮与太阳的视直径之比
+	DiameterRatio float64 `json:"diameter_ratio"`
+	// 食甚时太阳圆面被遮住的面积比例
+	Obscuration float64 `json:"obscuration"`
+	// 本影的直径(千米), 全食时为负数
+	CoreShadowDiameter float64 `json:"core_shadow_diameter"`
+	// 沙罗序列号, 以及在该序列中的序号
+	SarosSeries int `json:"saros_series"`
+	SarosMember int `json:"saros_member"`
+	// 中心线, 每5分钟一个点, 非中心食时为空
+	CentralLine []*EclipsePathPoint `json:"central_line"`
+}
+
+// LocalSolarEclipse 某地观测到的日食
+type LocalSolarEclipse struct {
+	// 日食类型, 以及 swe.EclVisible、swe.Ecl1stVisible 等可见性的标记
+	Type swe.EclipseType `json:"type"`
+	// 食甚
+	Maximum JulianDay `json:"maximum"`
+	// 初亏, 食既, 生光, 复圆; 偏食时没有食既和生光(为0)
+	FirstContact  JulianDay `json:"first_contact"`
+	SecondContact JulianDay `json:"second_contact"`
+	ThirdContact  JulianDay `json:"third_contact"`
+	FourthContact JulianDay `json:"fourth_contact"`
+	// 食分(NASA的定义)
+	Magnitude float64 `json:"magnitude"`
+	// 月亮与太阳的视直径之比
+	DiameterRatio float64 `json:"diameter_ratio"`
+	// 食甚时太阳圆面被遮住的面积比例
+	Obscuration float64 `json:"obscuration"`
+	// 食甚时太阳的地平坐标(含大气折射)
+	Horizontal *HorizontalCoordinates `json:"horizontal"`
+	// 沙罗序列号, 以及在该序列中的序号
+	SarosSeries int `json:"saros_series"`
+	SarosMember int `json:"saros_member"`
+}
+
+// LunarEclipse 月食
+type LunarEclipse struct {
+	// 月食类型, swe.EclTotal、swe.EclPartial、swe.EclPenumbral 之一
+	Type swe.EclipseType `json:"type"`
+	// 食甚
+	Maximum JulianDay `json:"maximum"`
+	// 半影食始 / 半影食终
+	PenumbralBegin JulianDay `json:"penumbral_begin"`
+	PenumbralEnd   JulianDay `json:"penumbral_end"`
+	// 初亏 / 复圆, 即月亮进入 / 离开本影, 半影月食时为0
+	PartialBegin JulianDay `json:"partial_begin"`
+	PartialEnd   JulianDay `json:"partial_end"`
+	// 食既 / 生光, 即月亮完全进入 / 开始离开本影, 非月全食时为0
+	TotalBegin JulianDay `json:"total_begin"`
+	TotalEnd   JulianDay `json:"total_end"`
+	// 本影食分
+	UmbralMagnitude float64 `json:"umbral_magnitude"`
+	// 半影食分
+	PenumbralMagnitude float64 `json:"penumbral_magnitude"`
+	// 沙罗序列号, 以及在该序列中的序号
+	SarosSeries int `json:"saros_series"`
+	SarosMember int `json:"saros_member"`
+}
+
+// LocalLunarEclipse 某地观测到的月食
+type LocalLunarEclipse struct {
+	LunarEclipse
+	// 月出 / 月落, 月食期间月亮没有升起或落下时为0
+	MoonRise JulianDay `json:"moon_rise"`
+	MoonSet  JulianDay `json:"moon_set"`
+	// 食甚时月亮的地平坐标(含大气折射)
+	Horizontal *HorizontalCoordinates `json:"horizontal"`
+}
+
+// GetSolarEclipseTypeString 日食类型的名称
+func GetSolarEclipseTypeString(t swe.EclipseType) string {
+	switch {
+	case t.Has(swe.EclAnnularTotal):
+		return "全环食"
+	case t.Has(swe.EclTotal):
+		return "全食"
+	case t.Has(swe.EclAnnular):
+		return "环食"
+	case t.Has(swe.EclPartial):
+		return "偏食"
+	}
+	return ""
+}
+
+// GetLunarEclipseTypeString 月食类型的名称
+func GetLunarEclipseTypeString(t swe.EclipseType) string {
+	switch {
+	case t.Has(swe.EclTotal):
+		return "月全食"
+	case t.Has(swe.EclPartial):
+		return "月偏食"
+	case t.Has(swe.EclPenumbral):
+		return "半影月食"
+	}
+	return ""
+}
+
+// swe的方位角以南为0°, 向西增加; 转为以北为0°, 向东增加的弧度
+func sweAzimuthToAzimuth(azimuth float64) float64 {
+	return RadiansMod360(ToRadians(azimuth + 180))
+}
+
+func (astro *Astronomy) eclipseFlags() *swe.EclipseFlags {
+	return &swe.EclipseFlags{Flags: swe.FlagEphSwiss}
+}
+
+// SolarEclipses 2时间之间的所有日食(全球)
+//	startJdUT 起始时间
+//	endJdUT 结束时间
+func (astro *Astronomy) SolarEclipses(startJdUT, endJdUT JulianDay) ([]*SolarEclipse, error) {
+	flags := astro.eclipseFlags()
+	var eclipses []*SolarEclipse
+
+	for jd := startJdUT; jd <= endJdUT; {
+		tret, typ, err := astro.Swe.SolEclipseWhenGlob(float64(jd), flags, 0, false)
+		if err != nil {
+			return nil, fmt.Errorf("SolarEclipses WhenGlob: %w", err)
+		}
+
+		maximum := JulianDay(tret[swe.SolEclGlobMax])
+		if maximum > endJdUT {
+			break
+		}
+
+		// 食甚的地点及该地的食分等
+		geopos, attr, _, err := astro.Swe.SolEclipseWhere(tret[swe.SolEclGlobMax], flags)
+		if err != nil {
+			return nil, fmt.Errorf("SolarEclipses Where: %w", err)
+		}
+
+		eclipse := &SolarEclipse{
+			Type:            typ,
+			Maximum:         maximum,
+			Begin:           JulianDay(tret[swe.SolEclGlobBegin]),
+			End:             JulianDay(tret[swe.SolEclGlobEnd]),
+			TotalityBegin:   JulianDay(tret[swe.SolEclGlobTotalBegin]),
+			TotalityEnd:     JulianDay(tret[swe.SolEclGlobTotalEnd]),
+			CenterLineBegin: JulianDay(tret[swe.SolEclGlobCenterBegin]),
+			CenterLineEnd:   JulianDay(tret[swe.SolEclGlobCenterEnd]),
+			GreatestEclipse: &GeographicCoordinates{
+				Longitude: ToRadians(geopos[swe.SolEclGeoLongitude]),
+				Latitude:  ToRadians(geopos[swe.SolEclGeoLatitude]),
+			},
+			Magnitude:          attr[swe.SolEclAttrMagnitude],
+			DiameterRatio:      attr[swe.SolEclAttrDiameterRatio],
+			Obscuration:        attr[swe.SolEclAttrObscuration],
+			CoreShadowDiameter: attr[swe.SolEclAttrCoreShadowDiameter],
+			SarosSeries:        int(attr[swe.SolEclAttrSarosSeries]),
+			SarosMember:        int(attr[swe.SolEclAttrSarosMember]),
+		}
+
+		// 中心食: 沿着中心线采样
+		if typ.Has(swe.EclCentral) && eclipse.CenterLineBegin > 0 {
+			eclipse.CentralLine, err = astro.solarEclipseCentralLine(eclipse.CenterLineBegin, eclipse.CenterLineEnd, flags)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		eclipses = append(eclipses, eclipse)
+
+		// 两次日食至少间隔接近一个朔望月, 从食甚后1天开始查找下一次
+		jd = maximum.Add(1)
+	}
+
+	return eclipses, nil
+}
+
+// solarEclipseCentralLine 中心线开始到结束之间, 每5分钟计算一次食甚所在的地点
+func (astro *Astronomy) solarEclipseCentralLine(startJdUT, endJdUT JulianDay, flags *swe.EclipseFlags) ([]*EclipsePathPoint, error) {
+	points := make([]*EclipsePathPoint, 0, int(float64(endJdUT-startJdUT)/solarEclipseCentralLineStep)+2)
+
+	for jd := startJdUT; ; jd = jd.Add(solarEclipseCentralLineStep) {
+		// 最后一个点落在中心线结束时
+		if jd > endJdUT {
+			jd = endJdUT
+		}
+
+		geopos, _, _, err := astro.Swe.SolEclipseWhere(float64(jd), flags)
+		if err != nil {
+			return nil, fmt.Errorf("SolarEclipses CentralLine: %w", err)
+		}
+
+		points = append(points, &EclipsePathPoint{
+			JdUT: jd,
+			Geographic: &GeographicCoordinates{
+				Longitude: ToRadians(geopos[swe.SolEclGeoLongitude]),
+				Latitude:  ToRadians(geopos[swe.SolEclGeoLatitude]),
+			},
+		})
+
+		if jd == endJdUT {
+			break
+		}
+	}
+
+	return points, nil
+}
+
+// SolarEclipsesWithObserver 2时间之间在某地可见的所有日食
+//	startJdUT 起始时间
+//	endJdUT 结束时间
+//	geo 观察者地理位置
+func (astro *Astronomy) SolarEclipsesWithObserver(startJdUT, endJdUT JulianDay, geo *GeographicCoordinates) ([]*LocalSolarEclipse, error) {
+	flags := astro.eclipseFlags()
+	geoLoc := &swe.GeoLoc{
+		Long: ToDegrees(geo.Longitude),
+		Lat:  ToDegrees(geo.Latitude),
+	}
+	var eclipses []*LocalSolarEclipse
+
+	for jd := startJdUT; jd <= endJdUT; {
+		tret, attr, typ, err := astro.Swe.SolEclipseWhenLoc(float64(jd), flags, geoLoc, false)
+		if err != nil {
+			return nil, fmt.Errorf("SolarEclipsesWithObserver WhenLoc: %w", err)
+		}
+
+		maximum := JulianDay(tret[swe.SolEclLocMax])
+		if maximum > endJdUT {
+			break
+		}
+
+		eclipses = append(eclipses, &LocalSolarEclipse{
+			Type:          typ,
+			Maximum:       maximum,
+			FirstContact:  JulianDay(tret[swe.SolEclLocFirstContact]),
+			SecondContact: JulianDay(tret[swe.SolEclLocSecondContact]),
+			ThirdContact:  JulianDay(tret[swe.SolEclLocThirdContact]),
+			FourthContact: JulianDay(tret[swe.SolEclLocFourthContact]),
+			Magnitude:     attr[swe.SolEclAttrMagnitude],
+			DiameterRatio: attr[swe.SolEclAttrDiameterRatio],
+			Obscuration:   attr[swe.SolEclAttrObscuration],
+			Horizontal: &HorizontalCoordinates{
+				Azimuth:  sweAzimuthToAzimuth(attr[swe.SolEclAttrAzimuth]),
+				Altitude: ToRadians(attr[swe.SolEclAttrApparentAltitude]),
+			},
+			SarosSeries: int(attr[swe.SolEclAttrSarosSeries]),
+			SarosMember: int(attr[swe.SolEclAttrSarosMember]),
+		})
+
+		jd = maximum.Add(1)
+	}
+
+	return eclipses, nil
+}
+
+func newLunarEclipse(typ swe.EclipseType, tret, attr []float64) *LunarEclipse {
+	return &LunarEclipse{
+		Type:               typ,
+		Maximum:            JulianDay(tret[swe.LunEclMax]),
+		PenumbralBegin:     JulianDay(tret[swe.LunEclPenumbralBegin]),
+		PenumbralEnd:       JulianDay(tret[swe.LunEclPenumbralEnd]),
+		PartialBegin:       JulianDay(tret[swe.LunEclPartialBegin]),
+		PartialEnd:         JulianDay(tret[swe.LunEclPartialEnd]),
+		TotalBegin:         JulianDay(tret[swe.LunEclTotalBegin]),
+		TotalEnd:           JulianDay(tret[swe.LunEclTotalEnd]),
+		UmbralMagnitude:    attr[swe.LunEclAttrUmbralMagnitude],
+		PenumbralMagnitude: attr[swe.LunEclAttrPenumbralMagnitude],
+		SarosSeries:        int(attr[swe.LunEclAttrSarosSeries]),
+		SarosMember:        int(attr[swe.LunEclAttrSarosMember]),
+	}
+}
+
+// LunarEclipsesRange 2时间之间的所有月食
+//	startJdUT 起始时间
+//	endJdUT 结束时间
+func (astro *Astronomy) LunarEclipsesRange(startJdUT, endJdUT JulianDay) ([]*LunarEclipse, error) {
+	flags := astro.eclipseFlags()
+	var eclipses []*LunarEclipse
+
+	for jd := startJdUT; jd <= endJdUT; {
+		tret, typ, err := astro.Swe.LunEclipseWhen(float64(jd), flags, 0, false)
+		if err != nil {
+			return nil, fmt.Errorf("LunarEclipses When: %w", err)
+		}
+
+		maximum := JulianDay(tret[swe.LunEclMax])
+		if maximum > endJdUT {
+			break
+		}
+
+		// 食甚时的食分
+		attr, _, err := astro.Swe.LunEclipseHow(tret[swe.LunEclMax], flags, nil)
+		if err != nil {
+			return nil, fmt.Errorf("LunarEclipses How: %w", err)
+		}
+
+		eclipses = append(eclipses, newLunarEclipse(typ, tret, attr))
+
+		// 两次月食至少间隔接近一个朔望月, 从食甚后1天开始查找下一次
+		jd = maximum.Add(1)
+	}
+
+	return eclipses, nil
+}
+
+// LunarEclipses 某年的月食
+//	year 年, 年初按照 Astronomy.CalendarReform 选择历法
+func (astro *Astronomy) LunarEclipses(year int) ([]*LunarEclipse, error) {
+	reform := astro.calendarReform()
+	jd := reform.DateToJulianDay(year, 1, 1, 0, 0, 0)
+	return astro.LunarEclipsesRange(jd, reform.AddYears(jd, 1))
+}
+
+// LunarEclipsesWithObserver 2时间之间在某地可见的所有月食
+//	startJdUT 起始时间
+//	endJdUT 结束时间
+//	geo 观察者地理位置
+func (astro *Astronomy) LunarEclipsesWithObserver(startJdUT, endJdUT JulianDay, geo *GeographicCoordinates) ([]*LocalLunarEclipse, error) {
+	flags := astro.eclipseFlags()
+	geoLoc := &swe.GeoLoc{
+		Long: ToDegrees(geo.Longitude),
+		Lat:  ToDegrees(geo.Latitude),
+	}
+	var eclipses []*LocalLunarEclipse
+
+	for jd := startJdUT; jd <= endJdUT; {
+		tret, attr, typ, err := astro.Swe.LunEclipseWhenLoc(float64(jd), flags, geoLoc, false)
+		if err != nil {
+			return nil, fmt.Errorf("LunarEclipsesWithObserver WhenLoc: %w", err)
+		}
+
+		maximum := JulianDay(tret[swe.LunEclMax])
+		if maximum > endJdUT {
+			break
+		}
+
+		eclipses = append(eclipses, &LocalLunarEclipse{
+			LunarEclipse: *newLunarEclipse(typ, tret, attr),
+			MoonRise:     JulianDay(tret[swe.LunEclMoonrise]),
+			MoonSet:      JulianDay(tret[swe.LunEclMoonset]),
+			Horizontal: &HorizontalCoordinates{
+				Azimuth:  sweAzimuthToAzimuth(attr[swe.LunEclAttrAzimuth]),
+				Altitude: ToRadians(attr[swe.LunEclAttrApparentAltitude]),
+			},
+		})
+
+		jd = maximum.Add(1)
+	}
+
+	return eclipses, nil
+}
@@ -0,0 +1,172 @@
+package astro
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FestivalType 节日的计算方式
+type FestivalType int
+
+const (
+	// FestivalLunar 按照阴历的月、日
+	FestivalLunar FestivalType = iota
+	// FestivalSolarTerm 按照节气
+	FestivalSolarTerm
+	// FestivalGregorian 按照公历的月、日
+	FestivalGregorian
+)
+
+// FestivalRule 节日的规则
+type FestivalRule struct {
+	// 名称
+	Name string `json:"name"`
+	// 计算方式
+	Type FestivalType `json:"type"`
+	// 月, 1~12; FestivalLunar 为阴历月(非闰月), FestivalGregorian 为公历月
+	Month int `json:"month"`
+	// 日; FestivalLunar 时负数为从月末倒数, -1 即该月的最后一天(腊月可能只有29天)
+	// 只能为 1~30 或 -30~-1, 超出该月天数的日期会被限制在该月内, 比如小月的30日即为29日
+	Day int `json:"day"`
+	// FestivalSolarTerm 时的节气, 见 SolarTermsString
+	SolarTerm int `json:"solar_term"`
+	// 在上述日期的基础上再偏移的天数, 比如寒食为清明的前一天: -1
+	Offset int `json:"offset"`
+}
+
+// Festival 节日
+type Festival struct {
+	// 名称
+	Name string `json:"name"`
+	// 计算方式
+	Type FestivalType `json:"type"`
+	// 该日当地(该历法的时区)0点的儒略日(UT)
+	JdUT JulianDay `json:"jd_ut"`
+}
+
+// ChineseFestivals 中国的传统节日
+var ChineseFestivals = []*FestivalRule{
+	{Name: "春节", Type: FestivalLunar, Month: 1, Day: 1},
+	{Name: "元宵", Type: FestivalLunar, Month: 1, Day: 15},
+	{Name: "龙抬头", Type: FestivalLunar, Month: 2, Day: 2},
+	{Name: "寒食", Type: FestivalSolarTerm, SolarTerm: 1, Offset: -1},
+	{Name: "清明", Type: FestivalSolarTerm, SolarTerm: 1},
+	{Name: "端午", Type: FestivalLunar, Month: 5, Day: 5},
+	{Name: "七夕", Type: FestivalLunar, Month: 7, Day: 7},
+	{Name: "中元", Type: FestivalLunar, Month: 7, Day: 15},
+	{Name: "中秋", Type: FestivalLunar, Month: 8, Day: 15},
+	{Name: "重阳", Type: FestivalLunar, Month: 9, Day: 9},
+	{Name: "冬至", Type: FestivalSolarTerm, SolarTerm: 18},
+	{Name: "腊八", Type: FestivalLunar, Month: 12, Day: 8},
+	{Name: "小年", Type: FestivalLunar, Month: 12, Day: 23},
+	{Name: "除夕", Type: FestivalLunar, Month: 12, Day: -1},
+}
+
+// VietnameseFestivals 越南的传统节日
+var VietnameseFestivals = []*FestivalRule{
+	{Name: "Tết Nguyên Đán", Type: FestivalLunar, Month: 1, Day: 1},
+	{Name: "Tết Nguyên Tiêu", Type: FestivalLunar, Month: 1, Day: 15},
+	{Name: "Giỗ Tổ Hùng Vương", Type: FestivalLunar, Month: 3, Day: 10},
+	{Name: "Tết Đoan Ngọ", Type: FestivalLunar, Month: 5, Day: 5},
+	{Name: "Tết Trung Thu", Type: FestivalLunar, Month: 8, Day: 15},
+	{Name: "Ông Công Ông Táo", Type: FestivalLunar, Month: 12, Day: 23},
+	{Name: "Giao Thừa", Type: FestivalLunar, Month: 12, Day: -1},
+}
+
+// KoreanFestivals 韩国的传统节日
+var KoreanFestivals = []*FestivalRule{
+	{Name: "설날", Type: FestivalLunar, Month: 1, Day: 1},
+	{Name: "정월 대보름", Type: FestivalLunar, Month: 1, Day: 15},
+	{Name: "한식", Type: FestivalSolarTerm, SolarTerm: 1, Offset: -1},
+	{Name: "단오", Type: FestivalLunar, Month: 5, Day: 5},
+	{Name: "추석", Type: FestivalLunar, Month: 8, Day: 15},
+	{Name: "동지", Type: FestivalSolarTerm, SolarTerm: 18},
+}
+
+// Festivals 某年(公历)的中国传统节日
+//	year 公历年
+func (astro *Astronomy) Festivals(year int) ([]*Festival, error) {
+	return astro.FestivalsWithCalendar(year, ChineseCalendar)
+}
+
+// FestivalsWithCalendar 某年(公历)中某个阴阳历的节日, 见 LunarCalendar.Festivals
+//	year 公历年
+//	cal 阴阳历
+func (astro *Astronomy) FestivalsWithCalendar(year int, cal *LunarCalendar) ([]*Festival, error) {
+	return astro.FestivalsByRules(year, cal, cal.Festivals)
+}
+
+// FestivalsByRules 按照自定义的规则计算某年(公历)的节日, 按时间排序
+//	year 公历年, 改历之前为儒略历年, 见 Astronomy.CalendarReform
+//	cal 阴阳历, 阴历的日期及当地0点都按照该历法计算
+//	rules 节日的规则
+func (astro *Astronomy) FestivalsByRules(year int, cal *LunarCalendar, rules []*FestivalRule) ([]*Festival, error) {
+	// 公历year年包含了农历year-1年的年末, 以及农历year年的大部分
+	months, err := astro.lunarYearMonthsRange(year-1, year+1, cal)
+	if err != nil {
+		return nil, fmt.Errorf("Festivals LunarMonths: %w", err)
+	}
+
+	// 该年的节气
+	reform := astro.calendarReform()
+	jd := reform.DateToJulianDay(year, 1, 1, 0, 0, 0)
+	solarTerms, err := astro.SolarTermsRange(jd, reform.AddYears(jd, 1))
+	if err != nil {
+		return nil, fmt.Errorf("Festivals SolarTerms: %w", err)
+	}
+
+	// 当地日期是否在公历year年
+	var inYear = func(jdz JulianDayWithLocation) bool {
+		y, _, _, _, _, _, _ := reform.ExtractJulianDay(JulianDay(jdz))
+		return y == year
+	}
+
+	var festivals []*Festival
+	var add = func(rule *FestivalRule, jdz JulianDayWithLocation) {
+		jdz = jdz.AddDays(rule.Offset)
+		if inYear(jdz) {
+			festivals = append(festivals, &Festival{
+				Name: rule.Name,
+				Type: rule.Type,
+				JdUT: jdz.ToJulianDay(cal.Offset),
+			})
+		}
+	}
+
+	for _, rule := range rules {
+		switch rule.Type {
+		case FestivalLunar:
+			if rule.Day == 0 || rule.Day > 30 || rule.Day < -30 {
+				return nil, fmt.Errorf("Festivals: %s has an invalid lunar day %d", rule.Name, rule.Day)
+			}
+			for _, month := range months {
+				if month.Leap || month.Index != rule.Month-1 || (month.Year != year-1 && month.Year != year) {
+					continue
+				}
+				day := IfThenElse(rule.Day < 0, month.Days+rule.Day+1, rule.Day).(int)
+				if day > month.Days {
+					day = month.Days
+				} else if day < 1 {
+					day = 1
+				}
+				add(rule, month.Start.AddDays(day-1))
+			}
+		case FestivalSolarTerm:
+			for _, solarTerm := range solarTerms {
+				if solarTerm.Index == rule.SolarTerm {
+					add(rule, solarTerm.JdUT.ToLocation(cal.Offset).StartOfDay())
+				}
+			}
+		case FestivalGregorian:
+			add(rule, JulianDayWithLocation(reform.DateToJulianDay(year, rule.Month, rule.Day, 0, 0, 0)))
+		default:
+			return nil, fmt.Errorf("Festivals: %s has an unknown type %d", rule.Name, rule.Type)
+		}
+	}
+
+	sort.SliceStable(festivals, func(i, j int) bool {
+		return festivals[i].JdUT < festivals[j].JdUT
+	})
+
+	return festivals, nil
+}
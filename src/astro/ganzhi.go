@@ -0,0 +1,129 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+	"math"
+	"time"
+)
+
+var HeavenlyStemStrings = [...]string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+var EarthlyBranchStrings = [...]string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+var ZodiacStrings = [...]string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+
+// GanZhi 干支
+type GanZhi struct {
+	// 天干 0~9, 见 HeavenlyStemStrings
+	Stem int `json:"stem"`
+	// 地支 0~11, 见 EarthlyBranchStrings
+	Branch int `json:"branch"`
+}
+
+// GanZhiDate 年、月、日、时的干支(四柱), 以及生肖
+type GanZhiDate struct {
+	Year  *GanZhi `json:"year"`
+	Month *GanZhi `json:"month"`
+	Day   *GanZhi `json:"day"`
+	Hour  *GanZhi `json:"hour"`
+	// 生肖 0~11, 见 ZodiacStrings, 即年支
+	Zodiac int `json:"zodiac"`
+}
+
+func intMod(v, n int) int {
+	return (v%n + n) % n
+}
+
+// NewGanZhi 六十甲子的第几个, 0为甲子, 59为癸亥
+func NewGanZhi(index int) *GanZhi {
+	index = intMod(index, 60)
+	return &GanZhi{Stem: index % 10, Branch: index % 12}
+}
+
+// Index 在六十甲子中的序号, 0为甲子
+func (gz *GanZhi) Index() int {
+	return intMod(6*gz.Stem-5*gz.Branch, 60)
+}
+
+func (gz *GanZhi) String() string {
+	return HeavenlyStemStrings[gz.Stem] + EarthlyBranchStrings[gz.Branch]
+}
+
+// ZodiacString 生肖的名称
+func (gzd *GanZhiDate) ZodiacString() string {
+	return ZodiacStrings[gzd.Zodiac]
+}
+
+// YearGanZhi 年的干支, 1984年为甲子年
+//	year 以立春为岁首的年
+func YearGanZhi(year int) *GanZhi {
+	return NewGanZhi(year - 1984)
+}
+
+// MonthGanZhi 月的干支, 以节为月首, 寅月(立春至惊蛰)为第一个月
+// 年上起月法: 甲己之年丙作首, 乙庚之岁戊为头...
+//	yearStem 年干
+//	month 立春后的第几个月, 0为寅月
+func MonthGanZhi(yearStem, month int) *GanZhi {
+	month = intMod(month, 12)
+	return &GanZhi{
+		Stem:   (yearStem%5*2 + 2 + month) % 10,
+		Branch: (month + 2) % 12,
+	}
+}
+
+// DayGanZhi 日的干支, 由儒略日数推算, 2000-01-01为戊午日
+//	jdz 当地时间的儒略日, 取其所在的日期
+func DayGanZhi(jdz JulianDayWithLocation) *GanZhi {
+	// 儒略日数(JDN)为当日正午的儒略日
+	jdn := int(math.Floor(float64(jdz) + .5))
+	return NewGanZhi(jdn + 49)
+}
+
+// HourGanZhi 时辰的干支, 23:00~00:59为子时
+// 日上起时法: 甲己还加甲, 乙庚丙作初...
+// 23点之后的子时, 按照次日起时
+//	dayStem 日干
+//	hour 当地时间的小时 0~23
+func HourGanZhi(dayStem, hour int) *GanZhi {
+	branch := (hour + 1) / 2 % 12
+	if hour >= 23 {
+		dayStem = (dayStem + 1) % 10
+	}
+	return &GanZhi{
+		Stem:   (dayStem%5*2 + branch) % 10,
+		Branch: branch,
+	}
+}
+
+// GanZhi 某时刻的年、月、日、时干支
+// 年以立春为界, 月以节为界(定气法, 即太阳黄经每30°), 日、时按照t所在时区的日期和时间
+//	t 时间
+func (astro *Astronomy) GanZhi(t time.Time) (*GanZhiDate, error) {
+	jdUT := astro.calendarReform().TimeToJulianDay(t)
+
+	sun, err := astro.PlanetProperties(swe.Sun, astro.NewEphemerisTime(jdUT))
+	if err != nil {
+		return nil, fmt.Errorf("GanZhi: %w", err)
+	}
+
+	// 立春(黄经315°)起, 每30°为一个月
+	month := int(RadiansMod360(sun.Ecliptic.Longitude-ToRadians(315)) / ToRadians(30))
+
+	// 立春之前(冬至至立春, 即丑月、子月的后半段)仍属于上一年
+	year, utcMonth, _, _, _, _, _ := astro.calendarReform().ExtractJulianDay(jdUT)
+	if utcMonth <= 6 && month >= 10 {
+		year--
+	}
+
+	_, offset := t.Zone()
+	day := DayGanZhi(jdUT.ToLocation(float64(offset) / 86400.))
+	yearGanZhi := YearGanZhi(year)
+
+	return &GanZhiDate{
+		Year:   yearGanZhi,
+		Month:  MonthGanZhi(yearGanZhi.Stem, month),
+		Day:    day,
+		Hour:   HourGanZhi(day.Stem, t.Hour()),
+		Zodiac: yearGanZhi.Branch,
+	}, nil
+}
@@ -7,6 +7,13 @@ import (
 )
 
 var LunarPhaseStrings = [...]string{"朔", "上弦", "望", "下弦"}
+
+// MoonPhaseNameStrings 8个月相的名称, 以月日黄经差每45°划分, 新月居中于0°
+var MoonPhaseNameStrings = [...]string{"新月", "蛾眉月", "上弦月", "盈凸月", "满月", "亏凸月", "下弦月", "残月"}
+var MoonPhaseEnglishNameStrings = [...]string{
+	"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous",
+	"Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent",
+}
 var LunarMonthStrings = [...]string{"正月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "腊月", "闰"}
 var LunarDayStrings = [...]string{
 	"初一", "初二", "初三", "初四", "初五", "初六", "初七", "初八", "初九",
@@ -68,7 +75,7 @@ func (astro *Astronomy) LunarSolarEclipticLongitudeDeltaToTime(jdET *EphemerisTi
 
 			lastJdUT = lastJdUT.Add(dayDelta)
 
-			lastDelta, lastSpeedDelta, err = astro.LunarSolarEclipticLongitudeDelta(NewEphemerisTime(lastJdUT))
+			lastDelta, lastSpeedDelta, err = astro.LunarSolarEclipticLongitudeDelta(astro.NewEphemerisTime(lastJdUT))
 			if err != nil {
 				return 0, 0, fmt.Errorf("LunarSolarEclipticLongitudeDeltaToTime Calc 2: %w", err)
 			}
@@ -94,7 +101,7 @@ func (astro *Astronomy) LunarSolarEclipticLongitudeDeltaToTime(jdET *EphemerisTi
 
 			// 渐步递增
 			lastJdUT = lastJdUT.Add(step)
-			lastDelta, lastSpeedDelta, err = astro.LunarSolarEclipticLongitudeDelta(NewEphemerisTime(lastJdUT))
+			lastDelta, lastSpeedDelta, err = astro.LunarSolarEclipticLongitudeDelta(astro.NewEphemerisTime(lastJdUT))
 			if err != nil {
 				return 0, calcCount, fmt.Errorf("LunarSolarEclipticLongitudeDeltaToTime Calc 2: %w", err)
 			}
@@ -119,7 +126,7 @@ func (astro *Astronomy) LunarSolarEclipticLongitudeDeltaToTime(jdET *EphemerisTi
 			// 两日期的平均值
 			meanJdUT = (savedJdUT + lastJdUT) / 2.
 			var lastDelta2 float64
-			lastDelta2, lastSpeedDelta, err = astro.LunarSolarEclipticLongitudeDelta(NewEphemerisTime(meanJdUT))
+			lastDelta2, lastSpeedDelta, err = astro.LunarSolarEclipticLongitudeDelta(astro.NewEphemerisTime(meanJdUT))
 			if err != nil {
 				return 0, calcCount, fmt.Errorf("LunarSolarEclipticLongitudeDeltaToTime Calc 3: %w", err)
 			}
@@ -164,7 +171,7 @@ func (astro *Astronomy) LunarSolarEclipticLongitudeDeltaToTimes(startJdUT Julian
 			jd += MeanLunarDays / 2
 		}
 
-		jd, _, err = astro.LunarSolarEclipticLongitudeDeltaToTime(NewEphemerisTime(jd), ToRadians(delta))
+		jd, _, err = astro.LunarSolarEclipticLongitudeDeltaToTime(astro.NewEphemerisTime(jd), ToRadians(delta))
 		if err != nil {
 			return nil, fmt.Errorf("LunarSolarEclipticLongitudeDeltaToTimes: %w", err)
 		}
@@ -183,15 +190,15 @@ func (astro *Astronomy) LunarPhasesRange(startJdUT, endJdUT JulianDay) ([]*Julia
 
 	// 90° 每个节气
 	const degreePerLunarPhases = 90
-	firstLongDelta, _, err := astro.LunarSolarEclipticLongitudeDelta(NewEphemerisTime(startJdUT))
+	firstLongDelta, _, err := astro.LunarSolarEclipticLongitudeDelta(astro.NewEphemerisTime(startJdUT))
 	if err != nil {
 		return nil, fmt.Errorf("LunarPhases Calc 1: %w", err)
 	}
 	// 第一个有效的角度
 	firstValidDelta := NextMultiples(ToDegrees(firstLongDelta), float64(degreePerLunarPhases))
 
-	// 计算1年大致有多少个朔望上下弦，多给1个
-	count := int(float64(endJdUT-startJdUT)/MeanLunarDays)*4 + 1
+	// 计算大致有多少个朔望上下弦，多给1个
+	count := int(math.Ceil(float64(endJdUT-startJdUT)/MeanLunarDays*4)) + 1
 	eclipticLongitudeDelta := make([]float64, count)
 	lunarTimes := make([]*JulianDayExtra, count)
 
@@ -214,19 +221,20 @@ func (astro *Astronomy) LunarPhasesRange(startJdUT, endJdUT JulianDay) ([]*Julia
 		lunarTimes[i].JdUT = jd
 	}
 
-	// 最后一个月的超过了结束时间，不返回
-	if times[count-1] > endJdUT {
-		return lunarTimes[0 : count-2], nil
+	// 超过了结束时间的，不返回
+	for count > 0 && times[count-1] > endJdUT {
+		count--
 	}
 
-	return lunarTimes, nil
+	return lunarTimes[0:count], nil
 }
 
 // LunarPhases 某年的月相
-//  year 年
+//  year 年, 年初按照 Astronomy.CalendarReform 选择历法
 func (astro *Astronomy) LunarPhases(year int) ([]*JulianDayExtra, error) {
-	jd := DateToJulianDay(year, 1, 1, 0, 0, 0)
-	return astro.LunarPhasesRange(jd, jd.AddYears(1))
+	reform := astro.calendarReform()
+	jd := reform.DateToJulianDay(year, 1, 1, 0, 0, 0)
+	return astro.LunarPhasesRange(jd, reform.AddYears(jd, 1))
 }
 
 // NextNewMoons 某时间之后的朔日（数组）
@@ -265,3 +273,60 @@ func (astro *Astronomy) LastNewMoons(startJdUT JulianDay) (JulianDay, error) {
 	// 原则上不可能出现这种错误
 	return 0, fmt.Errorf("LastNewMoons: Unknown error")
 }
+
+// MoonPhase 某时刻的月相
+type MoonPhase struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 月龄, 距上一个朔的天数
+	Age float64 `json:"age"`
+	// 上一个朔
+	NewMoon JulianDay `json:"new_moon"`
+	// 月日黄经差(弧度) 0 ~ 2π
+	EclipticLongitudeDelta float64 `json:"ecliptic_longitude_delta"`
+	// 距角, 与太阳的角距离(弧度)
+	Elongation float64 `json:"elongation"`
+	// 被照亮部分的比例, 0 ~ 1
+	Illumination float64 `json:"illumination"`
+	// 是否为盈(朔至望), 否则为亏
+	Waxing bool `json:"waxing"`
+	// 0~7, 见 MoonPhaseNameStrings, MoonPhaseEnglishNameStrings
+	Index       int    `json:"index"`
+	Name        string `json:"name"`
+	EnglishName string `json:"english_name"`
+}
+
+// MoonPhaseAt 某时刻的月龄、距角、被照亮的比例、盈亏, 以及8个月相之一
+//	jdUT 时间
+func (astro *Astronomy) MoonPhaseAt(jdUT JulianDay) (*MoonPhase, error) {
+	jdET := astro.NewEphemerisTime(jdUT)
+
+	delta, _, err := astro.LunarSolarEclipticLongitudeDelta(jdET)
+	if err != nil {
+		return nil, fmt.Errorf("MoonPhaseAt: %w", err)
+	}
+	delta = RadiansMod360(delta)
+
+	phenomena, err := astro.PlanetPhenomena(swe.Moon, jdET)
+	if err != nil {
+		return nil, fmt.Errorf("MoonPhaseAt: %w", err)
+	}
+
+	newMoon, err := astro.LastNewMoons(jdUT)
+	if err != nil {
+		return nil, fmt.Errorf("MoonPhaseAt: %w", err)
+	}
+
+	index := int(RadiansMod360(delta+Radian90/4)/(Radian90/2)) % 8
+	return &MoonPhase{
+		JdUT:                   jdUT,
+		Age:                    float64(jdUT - newMoon),
+		NewMoon:                newMoon,
+		EclipticLongitudeDelta: delta,
+		Elongation:             phenomena.Elongation,
+		Illumination:           phenomena.Illumination,
+		Waxing:                 delta < Radian180,
+		Index:                  index,
+		Name:                   MoonPhaseNameStrings[index],
+		EnglishName:            MoonPhaseEnglishNameStrings[index],
+	}, nil
+}
@@ -0,0 +1,291 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+	"math"
+	"sort"
+)
+
+// DefaultSupermoonMargin 超级月亮的默认阈值(千米), 朔、望时月地距离与最近的近地点距离之差不超过该值即为超级月亮
+const DefaultSupermoonMargin = 5000.
+
+type LunarApsisType int
+
+const (
+	// 近地点
+	LunarPerigee LunarApsisType = iota
+	// 远地点
+	LunarApogee
+)
+
+var LunarApsisStrings = [...]string{"近地点", "远地点"}
+
+// LunarApsis 月亮过近地点、远地点
+type LunarApsis struct {
+	JdUT JulianDay      `json:"jd_ut"`
+	Type LunarApsisType `json:"type"`
+	// 月地距离 单位是 千米
+	Distance float64 `json:"distance"`
+}
+
+// LunarNode 月亮过升交点、降交点, 即黄纬为0
+type LunarNode struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 升交点(由南向北)为true, 降交点为false
+	Ascending bool `json:"ascending"`
+	// 交点的黄经(弧度)
+	Longitude float64 `json:"longitude"`
+}
+
+// LunarDeclination 月亮赤纬的极值
+type LunarDeclination struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 最北为true, 最南为false
+	North bool `json:"north"`
+	// 赤纬(弧度)
+	Declination float64 `json:"declination"`
+}
+
+// LunarOrbitEvents 月亮轨道的事件
+type LunarOrbitEvents struct {
+	Apsides      []*LunarApsis       `json:"apsides"`
+	Nodes        []*LunarNode        `json:"nodes"`
+	Declinations []*LunarDeclination `json:"declinations"`
+}
+
+// LunarPhaseDetail 月相, 以及是否为超级月亮、蓝月亮
+type LunarPhaseDetail struct {
+	*JulianDayExtra
+	// 月地距离 单位是 千米
+	Distance float64 `json:"distance"`
+	// 时间上最近的近地点, 只有朔、望才有
+	Perigee *LunarApsis `json:"perigee,omitempty"`
+	// 朔、望时, 月地距离与最近的近地点距离之差不超过阈值
+	Supermoon bool `json:"supermoon"`
+	// 同一公历月的第二个望
+	BlueMoon bool `json:"blue_moon"`
+}
+
+// LunarApsides 时间范围内月亮的近地点、远地点, 按时间排序
+//	startJdUT, endJdUT 时间范围 [startJdUT, endJdUT)
+func (astro *Astronomy) LunarApsides(startJdUT, endJdUT JulianDay) ([]*LunarApsis, error) {
+	moon := func(jdUT JulianDay) (*PlanetProperties, error) {
+		return astro.PlanetProperties(swe.Moon, astro.NewEphemerisTime(jdUT))
+	}
+
+	// 距离的速度为0, 近点月约27.55天, 近地点、远地点的间隔不少于13天
+	roots, err := FindRoots(startJdUT, endJdUT, 1, func(jdUT JulianDay) (float64, error) {
+		props, err := moon(jdUT)
+		if err != nil {
+			return 0, err
+		}
+		return props.SpeedInDistance, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LunarApsides: %w", err)
+	}
+
+	var apsides []*LunarApsis
+	for _, jd := range roots {
+		props, err := moon(jd)
+		if err != nil {
+			return nil, fmt.Errorf("LunarApsides: %w", err)
+		}
+		after, err := moon(jd.Add(searchPrecision * 10))
+		if err != nil {
+			return nil, fmt.Errorf("LunarApsides: %w", err)
+		}
+		apsides = append(apsides, &LunarApsis{
+			JdUT:     jd,
+			Type:     IfThenElse(after.SpeedInDistance > 0, LunarPerigee, LunarApogee).(LunarApsisType),
+			Distance: props.DistanceAsKilometer(),
+		})
+	}
+	return apsides, nil
+}
+
+// LunarOrbitEvents 时间范围内月亮的近地点、远地点、升交点、降交点、赤纬极值, 均按时间排序
+//	startJdUT, endJdUT 时间范围 [startJdUT, endJdUT)
+func (astro *Astronomy) LunarOrbitEvents(startJdUT, endJdUT JulianDay) (*LunarOrbitEvents, error) {
+	apsides, err := astro.LunarApsides(startJdUT, endJdUT)
+	if err != nil {
+		return nil, fmt.Errorf("LunarOrbitEvents: %w", err)
+	}
+	return astro.lunarOrbitEvents(startJdUT, endJdUT, apsides)
+}
+
+// LunarOrbit 同 LunarOrbitEvents 和 LunarPhasesDetailRange, 近地点、远地点只计算一次
+func (astro *Astronomy) LunarOrbit(startJdUT, endJdUT JulianDay, supermoonMargin, offset float64) (*LunarOrbitEvents, []*LunarPhaseDetail, error) {
+	apsides, err := astro.LunarApsides(lunarPhasesApsidesRange(startJdUT, endJdUT))
+	if err != nil {
+		return nil, nil, fmt.Errorf("LunarOrbit: %w", err)
+	}
+
+	// 轨道事件只需要范围内的
+	var inRange []*LunarApsis
+	for _, apsis := range apsides {
+		if apsis.JdUT >= startJdUT && apsis.JdUT < endJdUT {
+			inRange = append(inRange, apsis)
+		}
+	}
+	events, err := astro.lunarOrbitEvents(startJdUT, endJdUT, inRange)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	phases, err := astro.lunarPhasesDetailRange(startJdUT, endJdUT, supermoonMargin, offset, apsides)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, phases, nil
+}
+
+// lunarOrbitEvents 见 LunarOrbitEvents, apsides 为已经计算好的范围内的近地点、远地点
+func (astro *Astronomy) lunarOrbitEvents(startJdUT, endJdUT JulianDay, apsides []*LunarApsis) (*LunarOrbitEvents, error) {
+	moon := func(jdUT JulianDay) (*PlanetProperties, error) {
+		return astro.PlanetProperties(swe.Moon, astro.NewEphemerisTime(jdUT))
+	}
+	// 月亮的赤纬和赤纬的速度
+	declination := func(jdUT JulianDay) (float64, float64, error) {
+		jdET := astro.NewEphemerisTime(jdUT)
+		flags := astro.simpleCalcFlags(jdET.DeltaT)
+		flags.Flags |= swe.FlagEquatorial
+		res, _, err := astro.Swe.Calc(jdET.Value(), swe.Moon, flags)
+		if err != nil {
+			return 0, 0, err
+		}
+		return res[1], res[4], nil
+	}
+
+	events := &LunarOrbitEvents{Apsides: apsides}
+
+	// 黄纬为0
+	nodes, err := FindRoots(startJdUT, endJdUT, 1, func(jdUT JulianDay) (float64, error) {
+		props, err := moon(jdUT)
+		if err != nil {
+			return 0, err
+		}
+		return props.Ecliptic.Latitude, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LunarOrbitEvents Nodes: %w", err)
+	}
+	for _, jd := range nodes {
+		props, err := moon(jd)
+		if err != nil {
+			return nil, fmt.Errorf("LunarOrbitEvents Nodes: %w", err)
+		}
+		events.Nodes = append(events.Nodes, &LunarNode{
+			JdUT:      jd,
+			Ascending: props.SpeedInLatitude > 0,
+			Longitude: props.Ecliptic.Longitude,
+		})
+	}
+
+	// 赤纬的速度为0
+	declinations, err := FindRoots(startJdUT, endJdUT, 1, func(jdUT JulianDay) (float64, error) {
+		_, speed, err := declination(jdUT)
+		return speed, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LunarOrbitEvents Declinations: %w", err)
+	}
+	for _, jd := range declinations {
+		value, _, err := declination(jd)
+		if err != nil {
+			return nil, fmt.Errorf("LunarOrbitEvents Declinations: %w", err)
+		}
+		_, after, err := declination(jd.Add(searchPrecision * 10))
+		if err != nil {
+			return nil, fmt.Errorf("LunarOrbitEvents Declinations: %w", err)
+		}
+		events.Declinations = append(events.Declinations, &LunarDeclination{
+			JdUT:        jd,
+			North:       after < 0,
+			Declination: value,
+		})
+	}
+
+	return events, nil
+}
+
+// LunarPhasesDetailRange 同 LunarPhasesRange, 并标记超级月亮、蓝月亮
+//	startJdUT, endJdUT 时间范围
+//	supermoonMargin 超级月亮的阈值(千米), 即与最近的近地点距离之差, <=0 时使用 DefaultSupermoonMargin
+//	offset 判断公历月所用的时区偏移(天), 比如 JD_CST_OFFSET
+func (astro *Astronomy) LunarPhasesDetailRange(startJdUT, endJdUT JulianDay, supermoonMargin, offset float64) ([]*LunarPhaseDetail, error) {
+	apsides, err := astro.LunarApsides(lunarPhasesApsidesRange(startJdUT, endJdUT))
+	if err != nil {
+		return nil, fmt.Errorf("LunarPhasesDetailRange: %w", err)
+	}
+	return astro.lunarPhasesDetailRange(startJdUT, endJdUT, supermoonMargin, offset, apsides)
+}
+
+// lunarPhasesApsidesRange LunarPhasesDetailRange 需要的近地点范围
+// 多算的一个月用于判断蓝月亮, 前后再各多算半个近点月, 保证每个朔、望都能找到最近的近地点
+func lunarPhasesApsidesRange(startJdUT, endJdUT JulianDay) (JulianDay, JulianDay) {
+	return startJdUT.Add(-MeanLunarDays - 15), endJdUT.Add(15)
+}
+
+// lunarPhasesDetailRange 见 LunarPhasesDetailRange, apsides 为 lunarPhasesApsidesRange 内的近地点、远地点
+func (astro *Astronomy) lunarPhasesDetailRange(startJdUT, endJdUT JulianDay, supermoonMargin, offset float64, apsides []*LunarApsis) ([]*LunarPhaseDetail, error) {
+	if supermoonMargin <= 0 {
+		supermoonMargin = DefaultSupermoonMargin
+	}
+
+	// 多计算一个月, 用于判断范围内第一个望是否为蓝月亮
+	phases, err := astro.LunarPhasesRange(startJdUT.Add(-MeanLunarDays-1), endJdUT)
+	if err != nil {
+		return nil, fmt.Errorf("LunarPhasesDetailRange: %w", err)
+	}
+	sort.SliceStable(phases, func(i, j int) bool {
+		return phases[i].JdUT < phases[j].JdUT
+	})
+
+	var perigees []*LunarApsis
+	for _, apsis := range apsides {
+		if apsis.Type == LunarPerigee {
+			perigees = append(perigees, apsis)
+		}
+	}
+
+	// 上一个望所在的公历年月
+	lastFullMoonMonth := 0
+
+	details := make([]*LunarPhaseDetail, 0, len(phases))
+	for _, phase := range phases {
+		moon, err := astro.PlanetProperties(swe.Moon, astro.NewEphemerisTime(phase.JdUT))
+		if err != nil {
+			return nil, fmt.Errorf("LunarPhasesDetailRange: %w", err)
+		}
+
+		detail := &LunarPhaseDetail{
+			JulianDayExtra: phase,
+			Distance:       moon.DistanceAsKilometer(),
+		}
+		// 只有朔、望才有超级月亮
+		if phase.Index == 0 || phase.Index == 2 {
+			for _, perigee := range perigees {
+				if detail.Perigee == nil || math.Abs(float64(perigee.JdUT-phase.JdUT)) < math.Abs(float64(detail.Perigee.JdUT-phase.JdUT)) {
+					detail.Perigee = perigee
+				}
+			}
+			if detail.Perigee != nil {
+				detail.Supermoon = detail.Distance-detail.Perigee.Distance <= supermoonMargin
+			}
+		}
+		if phase.Index == 2 {
+			year, month, _, _, _, _, _ := astro.calendarReform().ExtractJulianDay(JulianDay(phase.JdUT.ToLocation(offset)))
+			detail.BlueMoon = year*12+month == lastFullMoonMonth
+			lastFullMoonMonth = year*12 + month
+		}
+
+		if phase.JdUT < startJdUT {
+			continue
+		}
+		details = append(details, detail)
+	}
+
+	return details, nil
+}
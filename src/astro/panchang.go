@@ -0,0 +1,248 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+	"math"
+	"time"
+)
+
+var TithiStrings = [...]string{
+	"Shukla Pratipada", "Shukla Dwitiya", "Shukla Tritiya", "Shukla Chaturthi", "Shukla Panchami",
+	"Shukla Shashthi", "Shukla Saptami", "Shukla Ashtami", "Shukla Navami", "Shukla Dashami",
+	"Shukla Ekadashi", "Shukla Dwadashi", "Shukla Trayodashi", "Shukla Chaturdashi", "Purnima",
+	"Krishna Pratipada", "Krishna Dwitiya", "Krishna Tritiya", "Krishna Chaturthi", "Krishna Panchami",
+	"Krishna Shashthi", "Krishna Saptami", "Krishna Ashtami", "Krishna Navami", "Krishna Dashami",
+	"Krishna Ekadashi", "Krishna Dwadashi", "Krishna Trayodashi", "Krishna Chaturdashi", "Amavasya",
+}
+var NakshatraStrings = [...]string{
+	"Ashwini", "Bharani", "Krittika", "Rohini", "Mrigashira", "Ardra", "Punarvasu", "Pushya", "Ashlesha",
+	"Magha", "Purva Phalguni", "Uttara Phalguni", "Hasta", "Chitra", "Swati", "Vishakha", "Anuradha", "Jyeshtha",
+	"Mula", "Purva Ashadha", "Uttara Ashadha", "Shravana", "Dhanishta", "Shatabhisha", "Purva Bhadrapada", "Uttara Bhadrapada", "Revati",
+}
+var YogaStrings = [...]string{
+	"Vishkambha", "Priti", "Ayushman", "Saubhagya", "Shobhana", "Atiganda", "Sukarma", "Dhriti", "Shula",
+	"Ganda", "Vriddhi", "Dhruva", "Vyaghata", "Harshana", "Vajra", "Siddhi", "Vyatipata", "Variyana",
+	"Parigha", "Shiva", "Siddha", "Sadhya", "Shubha", "Shukla", "Brahma", "Indra", "Vaidhriti",
+}
+
+// KaranaStrings 前7个为循环的karana, 后4个为固定的karana
+var KaranaStrings = [...]string{
+	"Bava", "Balava", "Kaulava", "Taitila", "Garaja", "Vanija", "Vishti",
+	"Shakuni", "Chatushpada", "Naga", "Kimstughna",
+}
+var VaraStrings = [...]string{
+	"Ravivara", "Somavara", "Mangalavara", "Budhavara", "Guruvara", "Shukravara", "Shanivara",
+}
+
+const (
+	// 每个tithi 12°
+	tithiSpan = Radian360 / 30
+	// 每个karana 6°, 即半个tithi
+	karanaSpan = tithiSpan / 2
+	// 每个nakshatra、yoga 13°20′
+	nakshatraSpan = Radian360 / 27
+)
+
+// PanchangElement 五支(Panchang)中的一项
+type PanchangElement struct {
+	// 序号, 见 TithiStrings、NakshatraStrings、YogaStrings、KaranaStrings、VaraStrings
+	Index int `json:"index"`
+	// 名称
+	Name string `json:"name"`
+	// 开始、结束时间
+	Start JulianDay `json:"start"`
+	End   JulianDay `json:"end"`
+}
+
+// Panchang 印度历的五支: tithi(月日), nakshatra(月宿), yoga, karana(半个tithi), vara(星期), 均为日出时的值
+type Panchang struct {
+	// 日出
+	Sunrise JulianDay `json:"sunrise"`
+	// 岁差模式
+	Ayanamsa swe.Ayanamsa `json:"ayanamsa"`
+	// 日出时的岁差(弧度)
+	AyanamsaValue float64 `json:"ayanamsa_value"`
+
+	Tithi     *PanchangElement `json:"tithi"`
+	Nakshatra *PanchangElement `json:"nakshatra"`
+	// nakshatra的四分之一, 1~4
+	NakshatraPada int              `json:"nakshatra_pada"`
+	Yoga          *PanchangElement `json:"yoga"`
+	Karana        *PanchangElement `json:"karana"`
+	// 从日出到次日日出
+	Vara *PanchangElement `json:"vara"`
+}
+
+// karanaIndex 第几个半tithi(0~59)对应的karana
+// 第1个为Kimstughna, 最后3个为Shakuni、Chatushpada、Naga, 其它的为7个karana的循环
+func karanaIndex(halfTithi int) int {
+	switch {
+	case halfTithi == 0:
+		return 10
+	case halfTithi >= 57:
+		return halfTithi - 50
+	}
+	return (halfTithi - 1) % 7
+}
+
+// 牛顿迭代法: 从startJdUT开始, 求随时间递增的角度到达target的时间
+//	angle 返回jdUT时的角度及其速度(弧度/天)
+func calcJulianDayByAngle(startJdUT JulianDay, target float64, angle func(jdUT JulianDay) (float64, float64, error)) (JulianDay, error) {
+	value, speed, err := angle(startJdUT)
+	if err != nil {
+		return 0, err
+	}
+
+	jd := startJdUT
+	// 第一次向后推进到target, 之后在target附近修正
+	delta := RadiansMod360(target - value)
+	for calcCount := 0; !FloatEqual(delta, 0, 9); calcCount++ {
+		if calcCount > 50 {
+			return 0, fmt.Errorf("calcJulianDayByAngle: not converge")
+		}
+
+		jd = jd.Add(delta / speed)
+
+		if value, speed, err = angle(jd); err != nil {
+			return 0, err
+		}
+		delta = RadiansMod180(target - value)
+	}
+
+	return jd, nil
+}
+
+// Panchang 某地某日的印度历五支
+//	t 日期, 取t所在时区的年月日
+//	geo 观察者地理位置
+//	ayanamsa 岁差模式, 比如 swe.SidmLahiri
+func (astro *Astronomy) Panchang(t time.Time, geo *GeographicCoordinates, ayanamsa swe.Ayanamsa) (*Panchang, error) {
+	midnight := TimeToJulianDay(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+
+	// 日出, 以及次日的日出
+	sun, err := astro.RiseSetTransit(swe.Sun, midnight, geo, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Panchang Sunrise: %w", err)
+	}
+	if sun.State != TwilightNormal {
+		return nil, fmt.Errorf("Panchang Sunrise: the sun does not rise on %s", t.Format("2006-01-02"))
+	}
+	sunrise := sun.Rise
+	nextSun, err := astro.RiseSetTransit(swe.Sun, sunrise.Add(.5), geo, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Panchang Next Sunrise: %w", err)
+	}
+
+	// 岁差, 1天内变化不到0.2″, 视为常量
+	jdET := astro.NewEphemerisTime(sunrise)
+	aya, err := astro.Swe.GetAyanamsaEx(jdET.Value(), &swe.AyanamsaExFlags{
+		Flags:   swe.FlagEphSwiss,
+		SidMode: &swe.SidMode{Mode: ayanamsa},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Panchang Ayanamsa: %w", err)
+	}
+	aya = ToRadians(aya)
+
+	sunProps, err := astro.PlanetProperties(swe.Sun, jdET)
+	if err != nil {
+		return nil, fmt.Errorf("Panchang Sun: %w", err)
+	}
+	moonProps, err := astro.PlanetProperties(swe.Moon, jdET)
+	if err != nil {
+		return nil, fmt.Errorf("Panchang Moon: %w", err)
+	}
+
+	panchang := &Panchang{
+		Sunrise:       sunrise,
+		Ayanamsa:      ayanamsa,
+		AyanamsaValue: aya,
+	}
+
+	// tithi 和 karana: 月日的黄经差, 与岁差无关
+	elongation := RadiansMod360(moonProps.Ecliptic.Longitude - sunProps.Ecliptic.Longitude)
+	var elongationToTime = func(startJdUT JulianDay, delta float64) (JulianDay, error) {
+		jd, _, err := astro.LunarSolarEclipticLongitudeDeltaToTime(astro.NewEphemerisTime(startJdUT), RadiansMod360(delta))
+		return jd, err
+	}
+
+	tithi := int(elongation / tithiSpan)
+	panchang.Tithi = &PanchangElement{Index: tithi, Name: TithiStrings[tithi]}
+	// 一个tithi最长约26.8小时
+	if panchang.Tithi.Start, err = elongationToTime(sunrise.Add(-2), float64(tithi)*tithiSpan); err != nil {
+		return nil, fmt.Errorf("Panchang Tithi Start: %w", err)
+	}
+	if panchang.Tithi.End, err = elongationToTime(sunrise, float64(tithi+1)*tithiSpan); err != nil {
+		return nil, fmt.Errorf("Panchang Tithi End: %w", err)
+	}
+
+	halfTithi := int(elongation / karanaSpan)
+	karana := karanaIndex(halfTithi)
+	panchang.Karana = &PanchangElement{Index: karana, Name: KaranaStrings[karana]}
+	if panchang.Karana.Start, err = elongationToTime(sunrise.Add(-1), float64(halfTithi)*karanaSpan); err != nil {
+		return nil, fmt.Errorf("Panchang Karana Start: %w", err)
+	}
+	if panchang.Karana.End, err = elongationToTime(sunrise, float64(halfTithi+1)*karanaSpan); err != nil {
+		return nil, fmt.Errorf("Panchang Karana End: %w", err)
+	}
+
+	// nakshatra: 月亮的恒星黄经, 一个nakshatra最长约1.2天
+	moonSidereal := RadiansMod360(moonProps.Ecliptic.Longitude - aya)
+	nakshatra := int(moonSidereal / nakshatraSpan)
+	panchang.Nakshatra = &PanchangElement{Index: nakshatra, Name: NakshatraStrings[nakshatra]}
+	panchang.NakshatraPada = int(math.Mod(moonSidereal, nakshatraSpan)/(nakshatraSpan/4)) + 1
+
+	var moonToTime = func(startJdUT JulianDay, sidereal float64) (JulianDay, error) {
+		startJdET := astro.NewEphemerisTime(startJdUT)
+		planet, err := astro.PlanetProperties(swe.Moon, startJdET)
+		if err != nil {
+			return 0, err
+		}
+		jd, _, _, err := calcJulianDayBySolarEclipticLongitude(astro, startJdET, planet, RadiansMod360(sidereal+aya))
+		return jd, err
+	}
+	if panchang.Nakshatra.Start, err = moonToTime(sunrise.Add(-1.5), float64(nakshatra)*nakshatraSpan); err != nil {
+		return nil, fmt.Errorf("Panchang Nakshatra Start: %w", err)
+	}
+	if panchang.Nakshatra.End, err = moonToTime(sunrise, float64(nakshatra+1)*nakshatraSpan); err != nil {
+		return nil, fmt.Errorf("Panchang Nakshatra End: %w", err)
+	}
+
+	// yoga: 日月的恒星黄经之和
+	yogaSum := RadiansMod360(sunProps.Ecliptic.Longitude + moonProps.Ecliptic.Longitude - 2*aya)
+	yoga := int(yogaSum / nakshatraSpan)
+	panchang.Yoga = &PanchangElement{Index: yoga, Name: YogaStrings[yoga]}
+
+	var yogaAngle = func(jdUT JulianDay) (float64, float64, error) {
+		_jdET := astro.NewEphemerisTime(jdUT)
+		sun, err := astro.PlanetProperties(swe.Sun, _jdET)
+		if err != nil {
+			return 0, 0, err
+		}
+		moon, err := astro.PlanetProperties(swe.Moon, _jdET)
+		if err != nil {
+			return 0, 0, err
+		}
+		return RadiansMod360(sun.Ecliptic.Longitude + moon.Ecliptic.Longitude - 2*aya), sun.SpeedInLongitude + moon.SpeedInLongitude, nil
+	}
+	if panchang.Yoga.Start, err = calcJulianDayByAngle(sunrise.Add(-1.5), float64(yoga)*nakshatraSpan, yogaAngle); err != nil {
+		return nil, fmt.Errorf("Panchang Yoga Start: %w", err)
+	}
+	if panchang.Yoga.End, err = calcJulianDayByAngle(sunrise, RadiansMod360(float64(yoga+1)*nakshatraSpan), yogaAngle); err != nil {
+		return nil, fmt.Errorf("Panchang Yoga End: %w", err)
+	}
+
+	// vara: 日出所在的当地日期的星期, 儒略日数+1 除以7的余数, 0为星期日
+	_, offset := t.Zone()
+	jdn := int(math.Floor(float64(sunrise.ToLocation(float64(offset)/86400.)) + .5))
+	vara := (jdn + 1) % 7
+	panchang.Vara = &PanchangElement{
+		Index: vara,
+		Name:  VaraStrings[vara],
+		Start: sunrise,
+		End:   nextSun.Rise,
+	}
+
+	return panchang, nil
+}
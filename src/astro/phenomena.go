@@ -0,0 +1,47 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+)
+
+// PlanetPhenomena 天体的视现象
+type PlanetPhenomena struct {
+	PlanetId swe.Planet `json:"planet_id"`
+	// 相位角, 即 地球-天体-太阳 的夹角(弧度)
+	PhaseAngle float64 `json:"phase_angle"`
+	// 被照亮部分的比例, 0 ~ 1
+	Illumination float64 `json:"illumination"`
+	// 距角, 与太阳的角距离(弧度)
+	Elongation float64 `json:"elongation"`
+	// 视直径(弧度)
+	ApparentDiameter float64 `json:"apparent_diameter"`
+	// 视星等
+	Magnitude float64 `json:"magnitude"`
+	// 地平视差(弧度), 只有月亮有值
+	HorizontalParallax float64 `json:"horizontal_parallax"`
+}
+
+// PlanetPhenomena 天体的相位角、被照亮的比例、距角、视直径、视星等
+// 比通过 LunarSolarEclipticLongitudeDelta 估算的月相更准确, 因为考虑了黄纬和距离
+//	planetId 天体ID
+//	jdET 时间
+func (astro *Astronomy) PlanetPhenomena(planetId swe.Planet, jdET *EphemerisTime) (*PlanetPhenomena, error) {
+	attr, err := astro.Swe.Pheno(jdET.Value(), planetId, &swe.CalcFlags{
+		Flags:  swe.FlagEphSwiss,
+		DeltaT: &jdET.DeltaT,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PlanetPhenomena: %w", err)
+	}
+
+	return &PlanetPhenomena{
+		PlanetId:           planetId,
+		PhaseAngle:         ToRadians(attr[0]),
+		Illumination:       attr[1],
+		Elongation:         ToRadians(attr[2]),
+		ApparentDiameter:   ToRadians(attr[3]),
+		Magnitude:          attr[4],
+		HorizontalParallax: ToRadians(attr[5]),
+	}, nil
+}
@@ -0,0 +1,176 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+	"math"
+	"sort"
+)
+
+type PlanetEventType int
+
+const (
+	// 上合, 行星在太阳背后
+	SuperiorConjunction PlanetEventType = iota
+	// 下合, 内行星在地球与太阳之间
+	InferiorConjunction
+	// 冲, 外行星与太阳的黄经相差180°
+	Opposition
+	// 东大距, 内行星在太阳以东的最大距角, 黄昏可见
+	GreatestEasternElongation
+	// 西大距, 内行星在太阳以西的最大距角, 黎明可见
+	GreatestWesternElongation
+	// 东方照, 外行星在太阳以东90°
+	EasternQuadrature
+	// 西方照, 外行星在太阳以西90°
+	WesternQuadrature
+)
+
+var PlanetEventStrings = [...]string{"上合", "下合", "冲", "东大距", "西大距", "东方照", "西方照"}
+
+// PlanetEvent 行星的合、冲、大距、方照
+type PlanetEvent struct {
+	JdUT JulianDay       `json:"jd_ut"`
+	Type PlanetEventType `json:"type"`
+	// 距角, 与太阳的角距离(弧度)
+	Elongation float64 `json:"elongation"`
+	// 与地球的距离 单位是 AU
+	Distance float64 `json:"distance"`
+}
+
+// 数值求导的步长 单位是 天
+const elongationDerivativeStep = 0.01
+
+// 行星与太阳的黄经差, 以及距角、距离
+func (astro *Astronomy) planetSolarAngles(planet swe.Planet, jdUT JulianDay) (delta, deltaSpeed, elongation, distance float64, err error) {
+	jdET := astro.NewEphemerisTime(jdUT)
+	sun, err := astro.PlanetProperties(swe.Sun, jdET)
+	if err != nil {
+		return
+	}
+	props, err := astro.PlanetProperties(planet, jdET)
+	if err != nil {
+		return
+	}
+
+	delta = RadiansMod180(props.Ecliptic.Longitude - sun.Ecliptic.Longitude)
+	deltaSpeed = props.SpeedInLongitude - sun.SpeedInLongitude
+	elongation = math.Acos(math.Sin(props.Ecliptic.Latitude)*math.Sin(sun.Ecliptic.Latitude) +
+		math.Cos(props.Ecliptic.Latitude)*math.Cos(sun.Ecliptic.Latitude)*math.Cos(delta))
+	distance = props.Distance
+	return
+}
+
+// PlanetEvents 时间范围内水星~海王星的合、冲、大距、方照, 按时间排序
+// 以行星会合周期(PlanetaryRendezvousPeriod)的1/12为步长采样, 再求精确的时刻
+// 合、冲、方照以黄经差计算, 大距以距角(含黄纬)的极大值计算
+//	planet 行星, swe.Mercury ~ swe.Neptune
+//	startJdUT, endJdUT 时间范围 [startJdUT, endJdUT)
+func (astro *Astronomy) PlanetEvents(planet swe.Planet, startJdUT, endJdUT JulianDay) ([]*PlanetEvent, error) {
+	if planet < swe.Mercury || planet > swe.Neptune {
+		return nil, fmt.Errorf("PlanetEvents: planet must be Mercury ~ Neptune, got %s", planet)
+	}
+
+	inferior := planet == swe.Mercury || planet == swe.Venus
+	step := PlanetaryRendezvousPeriod[planet-swe.Mercury] / 12
+
+	// 内行星只有合, 外行星有合、冲、东方照、西方照
+	targets := []float64{0}
+	if !inferior {
+		targets = append(targets, Radian180, Radian90, -Radian90)
+	}
+
+	crossings, err := FindAngleCrossings(startJdUT, endJdUT, step, targets, func(jdUT JulianDay) (float64, float64, error) {
+		delta, deltaSpeed, _, _, err := astro.planetSolarAngles(planet, jdUT)
+		return delta, deltaSpeed, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PlanetEvents: %w", err)
+	}
+
+	var events []*PlanetEvent
+	for _, crossing := range crossings {
+		_, _, elongation, distance, err := astro.planetSolarAngles(planet, crossing.JdUT)
+		if err != nil {
+			return nil, fmt.Errorf("PlanetEvents: %w", err)
+		}
+
+		var _type PlanetEventType
+		switch crossing.Index {
+		case 0:
+			// 行星比太阳远即为上合
+			sun, err := astro.PlanetProperties(swe.Sun, astro.NewEphemerisTime(crossing.JdUT))
+			if err != nil {
+				return nil, fmt.Errorf("PlanetEvents: %w", err)
+			}
+			_type = IfThenElse(distance > sun.Distance, SuperiorConjunction, InferiorConjunction).(PlanetEventType)
+		case 1:
+			_type = Opposition
+		case 2:
+			_type = EasternQuadrature
+		case 3:
+			_type = WesternQuadrature
+		}
+
+		events = append(events, &PlanetEvent{JdUT: crossing.JdUT, Type: _type, Elongation: elongation, Distance: distance})
+	}
+
+	if inferior {
+		elongations, err := astro.greatestElongations(planet, startJdUT, endJdUT, step)
+		if err != nil {
+			return nil, fmt.Errorf("PlanetEvents: %w", err)
+		}
+		events = append(events, elongations...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].JdUT < events[j].JdUT
+	})
+	return events, nil
+}
+
+// greatestElongations 内行星的东大距、西大距, 即距角变化率由正变负的时刻
+func (astro *Astronomy) greatestElongations(planet swe.Planet, startJdUT, endJdUT JulianDay, step float64) ([]*PlanetEvent, error) {
+	// 距角的变化率(中心差分)
+	elongationSpeed := func(jdUT JulianDay) (float64, error) {
+		_, _, e1, _, err := astro.planetSolarAngles(planet, jdUT.Add(-elongationDerivativeStep))
+		if err != nil {
+			return 0, err
+		}
+		_, _, e2, _, err := astro.planetSolarAngles(planet, jdUT.Add(elongationDerivativeStep))
+		if err != nil {
+			return 0, err
+		}
+		return e2 - e1, nil
+	}
+
+	roots, err := FindRoots(startJdUT, endJdUT, step, elongationSpeed)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*PlanetEvent
+	for _, jd := range roots {
+		// 距角的极小值在合的附近, 忽略
+		speed, err := elongationSpeed(jd.Add(elongationDerivativeStep))
+		if err != nil {
+			return nil, err
+		}
+		if speed > 0 {
+			continue
+		}
+
+		delta, _, elongation, distance, err := astro.planetSolarAngles(planet, jd)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &PlanetEvent{
+			JdUT:       jd,
+			Type:       IfThenElse(delta > 0, GreatestEasternElongation, GreatestWesternElongation).(PlanetEventType),
+			Elongation: elongation,
+			Distance:   distance,
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,199 @@
+package astro
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// 根的精度 单位是 天, 约为0.01秒
+const searchPrecision = 1e-7
+
+// ScalarFunc 随时间变化的函数
+type ScalarFunc func(jdUT JulianDay) (float64, error)
+
+// AngleFunc 随时间变化的角度, 返回角度(弧度)以及其变化速度(弧度/天)
+type AngleFunc func(jdUT JulianDay) (value, speed float64, err error)
+
+// refineRoot 在[a, b]内求 fn 的根, fa、fb异号
+// 使用Illinois改进的试位法, 不需要导数, 并保证根始终在区间内
+func refineRoot(a, b JulianDay, fa, fb float64, fn ScalarFunc) (JulianDay, error) {
+	if fa == 0 {
+		return a, nil
+	} else if fb == 0 {
+		return b, nil
+	}
+
+	side := 0
+	for calcCount := 0; calcCount < 100; calcCount++ {
+		if float64(b-a) < searchPrecision {
+			break
+		}
+
+		c := JulianDay((float64(a)*fb - float64(b)*fa) / (fb - fa))
+		// 数值误差导致落在区间外时, 取中点
+		if c <= a || c >= b {
+			c = a.Add(float64(b-a) / 2)
+		}
+
+		fc, err := fn(c)
+		if err != nil {
+			return 0, err
+		}
+		if fc == 0 {
+			return c, nil
+		}
+
+		if SameSign(fc, fb) {
+			b, fb = c, fc
+			if side == -1 {
+				fa /= 2
+			}
+			side = -1
+		} else {
+			a, fa = c, fc
+			if side == 1 {
+				fb /= 2
+			}
+			side = 1
+		}
+	}
+
+	return a.Add(float64(b-a) / 2), nil
+}
+
+// FindRoots 在[startJdUT, endJdUT)内, 找出 fn 变号的所有时刻
+// 以step为步长采样, 步长内变号多次(偶数次)的根会被遗漏, 所以step需小于根的最小间隔
+//	step 采样的步长 单位是 天
+func FindRoots(startJdUT, endJdUT JulianDay, step float64, fn ScalarFunc) ([]JulianDay, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("FindRoots: step must be positive")
+	}
+
+	var roots []JulianDay
+
+	a := startJdUT
+	fa, err := fn(a)
+	if err != nil {
+		return nil, fmt.Errorf("FindRoots: %w", err)
+	}
+	for a < endJdUT {
+		b := JulianDay(math.Min(float64(a.Add(step)), float64(endJdUT)))
+		fb, err := fn(b)
+		if err != nil {
+			return nil, fmt.Errorf("FindRoots: %w", err)
+		}
+
+		// 根在b上时, 留给下一个区间
+		if fa == 0 || (fb != 0 && !SameSign(fa, fb)) {
+			root, err := refineRoot(a, b, fa, fb, fn)
+			if err != nil {
+				return nil, fmt.Errorf("FindRoots: %w", err)
+			}
+			roots = append(roots, root)
+		}
+
+		a, fa = b, fb
+	}
+
+	return roots, nil
+}
+
+type angleSample struct {
+	jd    JulianDay
+	value float64
+	speed float64
+}
+
+// FindAngleCrossings 在[startJdUT, endJdUT)内, 找出角度经过各个目标角度的所有时刻
+// 先以step为步长采样, 在速度变号(留)的位置二分出留的时刻, 使每一段的角度单调变化,
+// 然后在每一段内求目标角度的根, 所以逆行时3次经过同一角度都可以找出
+// step需保证每一步的角度变化小于180°, 且步长内最多只有一次留
+//	step 采样的步长 单位是 天
+//	targets 目标角度(弧度)的数组
+//	angle 角度函数
+// 返回的 JulianDayExtra 中 Index 为 targets 的下标, 按时间排序
+func FindAngleCrossings(startJdUT, endJdUT JulianDay, step float64, targets []float64, angle AngleFunc) ([]*JulianDayExtra, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("FindAngleCrossings: step must be positive")
+	}
+
+	sample := func(jd JulianDay) (*angleSample, error) {
+		value, speed, err := angle(jd)
+		if err != nil {
+			return nil, err
+		}
+		return &angleSample{jd: jd, value: value, speed: speed}, nil
+	}
+	speedFunc := func(jd JulianDay) (float64, error) {
+		_, speed, err := angle(jd)
+		return speed, err
+	}
+
+	var results []*JulianDayExtra
+
+	// 单调区间内求各个目标角度的根
+	searchSegment := func(a, b *angleSample) error {
+		for index, target := range targets {
+			fa := RadiansMod180(a.value - target)
+			fb := RadiansMod180(b.value - target)
+
+			// 根在b上时, 留给下一个区间
+			if fb == 0 || (fa != 0 && SameSign(fa, fb)) {
+				continue
+			}
+			// 单调区间内的角度变化小于180°, 跨越±180°的变号不是根
+			if math.Abs(fa-fb) > math.Pi {
+				continue
+			}
+
+			root, err := refineRoot(a.jd, b.jd, fa, fb, func(jd JulianDay) (float64, error) {
+				value, _, err := angle(jd)
+				return RadiansMod180(value - target), err
+			})
+			if err != nil {
+				return err
+			}
+			results = append(results, NewJulianDayExtra(root, index))
+		}
+		return nil
+	}
+
+	a, err := sample(startJdUT)
+	if err != nil {
+		return nil, fmt.Errorf("FindAngleCrossings: %w", err)
+	}
+	for a.jd < endJdUT {
+		b, err := sample(JulianDay(math.Min(float64(a.jd.Add(step)), float64(endJdUT))))
+		if err != nil {
+			return nil, fmt.Errorf("FindAngleCrossings: %w", err)
+		}
+
+		if a.speed != 0 && b.speed != 0 && !SameSign(a.speed, b.speed) {
+			// 留, 以留为界拆分为两段
+			jd, err := refineRoot(a.jd, b.jd, a.speed, b.speed, speedFunc)
+			if err != nil {
+				return nil, fmt.Errorf("FindAngleCrossings Station: %w", err)
+			}
+			station, err := sample(jd)
+			if err != nil {
+				return nil, fmt.Errorf("FindAngleCrossings: %w", err)
+			}
+			if err = searchSegment(a, station); err != nil {
+				return nil, fmt.Errorf("FindAngleCrossings: %w", err)
+			}
+			if err = searchSegment(station, b); err != nil {
+				return nil, fmt.Errorf("FindAngleCrossings: %w", err)
+			}
+		} else if err = searchSegment(a, b); err != nil {
+			return nil, fmt.Errorf("FindAngleCrossings: %w", err)
+		}
+
+		a = b
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].JdUT < results[j].JdUT
+	})
+	return results, nil
+}
@@ -0,0 +1,93 @@
+package astro
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindRoots(t *testing.T) {
+	linear := func(root float64) ScalarFunc {
+		return func(jdUT JulianDay) (float64, error) {
+			return float64(jdUT) - root, nil
+		}
+	}
+	tests := []struct {
+		name       string
+		start, end JulianDay
+		step       float64
+		fn         ScalarFunc
+		want       []float64
+	}{
+		{
+			name:  "sin",
+			start: 0.5,
+			end:   10,
+			step:  0.5,
+			fn: func(jdUT JulianDay) (float64, error) {
+				return math.Sin(float64(jdUT)), nil
+			},
+			want: []float64{math.Pi, 2 * math.Pi, 3 * math.Pi},
+		},
+		{name: "root inside a step", start: 0, end: 4, step: 1, fn: linear(2.5), want: []float64{2.5}},
+		{name: "root on a sample point", start: 0, end: 4, step: 1, fn: linear(2), want: []float64{2}},
+		{name: "root on the start", start: 0, end: 4, step: 1, fn: linear(0), want: []float64{0}},
+		{name: "root on the end", start: 0, end: 4, step: 1, fn: linear(4), want: nil},
+		{name: "step longer than the range", start: 0, end: 4, step: 10, fn: linear(3), want: []float64{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots, err := FindRoots(tt.start, tt.end, tt.step, tt.fn)
+			if err != nil {
+				t.Fatalf("FindRoots() error = %v", err)
+			}
+			if len(roots) != len(tt.want) {
+				t.Fatalf("FindRoots() = %v, want %v", roots, tt.want)
+			}
+			for i, root := range roots {
+				if math.Abs(float64(root)-tt.want[i]) > 1e-6 {
+					t.Errorf("roots[%d] = %f, want %f", i, root, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindRootsStep(t *testing.T) {
+	fn := func(jdUT JulianDay) (float64, error) {
+		return float64(jdUT), nil
+	}
+	for _, step := range []float64{0, -1} {
+		if _, err := FindRoots(0, 1, step, fn); err == nil {
+			t.Errorf("FindRoots() with step %f should return an error", step)
+		}
+	}
+}
+
+func TestRefineRoot(t *testing.T) {
+	fn := func(jdUT JulianDay) (float64, error) {
+		return math.Pow(float64(jdUT), 3) - 2, nil
+	}
+	tests := []struct {
+		name   string
+		a, b   JulianDay
+		fa, fb float64
+		want   float64
+	}{
+		{name: "cube root", a: 0, b: 2, fa: -2, fb: 6, want: math.Cbrt(2)},
+		{name: "root on a", a: 1, b: 2, fa: 0, fb: 6, want: 1},
+		{name: "root on b", a: 0, b: 1, fa: -2, fb: 0, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := refineRoot(tt.a, tt.b, tt.fa, tt.fb, fn)
+			if err != nil {
+				t.Fatalf("refineRoot() error = %v", err)
+			}
+			if math.Abs(float64(root)-tt.want) > 1e-6 {
+				t.Errorf("refineRoot() = %f, want %f", root, tt.want)
+			}
+		})
+	}
+}
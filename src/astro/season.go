@@ -0,0 +1,132 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+)
+
+// SeasonEventStrings 二分二至, 以月份命名, 与南北半球无关
+var SeasonEventStrings = [...]string{"March equinox", "June solstice", "September equinox", "December solstice"}
+
+// NorthernSeasonEventStrings 北半球的名称
+var NorthernSeasonEventStrings = [...]string{"Vernal equinox", "Summer solstice", "Autumnal equinox", "Winter solstice"}
+
+// SouthernSeasonEventStrings 南半球的名称
+var SouthernSeasonEventStrings = [...]string{"Autumnal equinox", "Winter solstice", "Vernal equinox", "Summer solstice"}
+
+// NorthernSeasonStrings 北半球的季节, 与 SeasonEventStrings 一一对应, 即该节点开始的季节
+var NorthernSeasonStrings = [...]string{"Spring", "Summer", "Autumn", "Winter"}
+
+// SouthernSeasonStrings 南半球的季节
+var SouthernSeasonStrings = [...]string{"Autumn", "Winter", "Spring", "Summer"}
+
+// SeasonEvent 二分二至
+type SeasonEvent struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 0~3, 见 SeasonEventStrings
+	Index    int    `json:"index"`
+	Name     string `json:"name"`
+	Northern string `json:"northern"`
+	Southern string `json:"southern"`
+}
+
+// Season 天文季节, 由二分二至划分
+type Season struct {
+	// 0~3, 见 NorthernSeasonStrings, SouthernSeasonStrings
+	Index    int    `json:"index"`
+	Northern string `json:"northern"`
+	Southern string `json:"southern"`
+	// [Start, End)
+	Start JulianDay `json:"start"`
+	End   JulianDay `json:"end"`
+	Days  float64   `json:"days"`
+}
+
+// EarthApsis 地球过近日点、远日点
+type EarthApsis struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 近日点为true, 远日点为false
+	Perihelion bool `json:"perihelion"`
+	// 日地距离 单位是 AU
+	Distance float64 `json:"distance"`
+}
+
+// Seasons 某年的四季
+type Seasons struct {
+	Year   int            `json:"year"`
+	Events []*SeasonEvent `json:"events"`
+	// 该年的二分二至开始的4个季节, 最后一个季节结束于次年的3月分点
+	Seasons []*Season     `json:"seasons"`
+	Apsides []*EarthApsis `json:"apsides"`
+}
+
+// Seasons 某年的二分二至、四季的长度、近日点和远日点
+//	year 年, 年初按照 Astronomy.CalendarReform 选择历法
+func (astro *Astronomy) Seasons(year int) (*Seasons, error) {
+	reform := astro.calendarReform()
+	start := reform.DateToJulianDay(year, 1, 1, 0, 0, 0)
+	end := reform.AddYears(start, 1)
+
+	// 多算到次年的4月, 得到最后一个季节的结束
+	ingresses, err := astro.Ingresses(swe.Sun, start, end.Add(100), 0, Radian90, Radian180, Radian90*3)
+	if err != nil {
+		return nil, fmt.Errorf("Seasons: %w", err)
+	}
+
+	seasons := &Seasons{Year: year}
+	for i, ingress := range ingresses {
+		if ingress.JdUT >= end {
+			break
+		}
+		seasons.Events = append(seasons.Events, &SeasonEvent{
+			JdUT:     ingress.JdUT,
+			Index:    ingress.Index,
+			Name:     SeasonEventStrings[ingress.Index],
+			Northern: NorthernSeasonEventStrings[ingress.Index],
+			Southern: SouthernSeasonEventStrings[ingress.Index],
+		})
+
+		if i+1 < len(ingresses) {
+			seasons.Seasons = append(seasons.Seasons, &Season{
+				Index:    ingress.Index,
+				Northern: NorthernSeasonStrings[ingress.Index],
+				Southern: SouthernSeasonStrings[ingress.Index],
+				Start:    ingress.JdUT,
+				End:      ingresses[i+1].JdUT,
+				Days:     float64(ingresses[i+1].JdUT - ingress.JdUT),
+			})
+		}
+	}
+
+	// 日地距离的极值, 间隔约半年
+	sun := func(jdUT JulianDay) (*PlanetProperties, error) {
+		return astro.PlanetProperties(swe.Sun, astro.NewEphemerisTime(jdUT))
+	}
+	roots, err := FindRoots(start, end, 10, func(jdUT JulianDay) (float64, error) {
+		props, err := sun(jdUT)
+		if err != nil {
+			return 0, err
+		}
+		return props.SpeedInDistance, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Seasons Apsides: %w", err)
+	}
+	for _, jd := range roots {
+		props, err := sun(jd)
+		if err != nil {
+			return nil, fmt.Errorf("Seasons Apsides: %w", err)
+		}
+		after, err := sun(jd.Add(searchPrecision * 10))
+		if err != nil {
+			return nil, fmt.Errorf("Seasons Apsides: %w", err)
+		}
+		seasons.Apsides = append(seasons.Apsides, &EarthApsis{
+			JdUT:       jd,
+			Perihelion: after.SpeedInDistance > 0,
+			Distance:   props.Distance,
+		})
+	}
+
+	return seasons, nil
+}
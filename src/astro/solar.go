@@ -42,7 +42,7 @@ func calcJulianDayBySolarEclipticLongitude(
 		_jd = _jd.Add(dayDelta)
 
 		var err error
-		_lastPlanet, err = astro.PlanetProperties(_lastPlanet.PlanetId, NewEphemerisTime(_jd))
+		_lastPlanet, err = astro.PlanetProperties(_lastPlanet.PlanetId, astro.NewEphemerisTime(_jd))
 		if err != nil {
 			return 0, nil, calcCount, fmt.Errorf("calcJulianDayBySolarEclipticLongitude: %w", err)
 		}
@@ -58,7 +58,7 @@ func calcJulianDayBySolarEclipticLongitude(
 //	startJdUT 时间起始
 //	eclipticLongitude 黄经弧度
 func (astro *Astronomy) SolarEclipticLongitudesToTime(startJdUT JulianDay, eclipticLongitude float64) (JulianDay, int, error) {
-	jdET := NewEphemerisTime(startJdUT)
+	jdET := astro.NewEphemerisTime(startJdUT)
 
 	// startJd的黄经
 	planet, err := astro.PlanetProperties(swe.Sun, jdET)
@@ -82,7 +82,7 @@ func (astro *Astronomy) SolarEclipticLongitudesToTimes(startJdUT JulianDay, ecli
 	times := make([]JulianDay, len(eclipticLongitudes))
 	var jd = startJdUT
 
-	planet, err := astro.PlanetProperties(swe.Sun, NewEphemerisTime(jd))
+	planet, err := astro.PlanetProperties(swe.Sun, astro.NewEphemerisTime(jd))
 	if err != nil {
 		return nil, fmt.Errorf("SolarEclipticLongitudesToTimes Calc 1: %w", err)
 	}
@@ -92,12 +92,12 @@ func (astro *Astronomy) SolarEclipticLongitudesToTimes(startJdUT JulianDay, ecli
 		// 如果上一个弧度差和现在的相等，为了避免SolarEclipticLongitudesToTime不往后面推进，jd累加半年、planet重新计算
 		if FloatEqual(angle, lastAngle, 9) {
 			jd += MeanSolarDays * .8
-			planet, err = astro.PlanetProperties(swe.Sun, NewEphemerisTime(jd))
+			planet, err = astro.PlanetProperties(swe.Sun, astro.NewEphemerisTime(jd))
 			if err != nil {
 				return nil, fmt.Errorf("SolarEclipticLongitudesToTimes Calc 3: %w", err)
 			}
 		}
-		jd, planet, _, err = calcJulianDayBySolarEclipticLongitude(astro, NewEphemerisTime(jd), planet, angle)
+		jd, planet, _, err = calcJulianDayBySolarEclipticLongitude(astro, astro.NewEphemerisTime(jd), planet, angle)
 		if err != nil {
 			return nil, fmt.Errorf("SolarEclipticLongitudesToTimes Calc 2: %w", err)
 		}
@@ -120,7 +120,7 @@ func (astro *Astronomy) SolarTermsRange(startJdUT, endJdUT JulianDay) ([]*Julian
 	solarTerms := make([]*JulianDayExtra, 0, int(float64(endJdUT-startJdUT)/MeanSolarDays*solarTermCount))
 
 	// 计算startJdUT的黄经
-	planet, err := astro.PlanetProperties(swe.Sun, NewEphemerisTime(startJdUT))
+	planet, err := astro.PlanetProperties(swe.Sun, astro.NewEphemerisTime(startJdUT))
 	if err != nil {
 		return nil, fmt.Errorf("SolarTerms Calc 1: %w", err)
 	}
@@ -147,8 +147,9 @@ func (astro *Astronomy) SolarTermsRange(startJdUT, endJdUT JulianDay) ([]*Julian
 }
 
 // SolarTerms 该年的24节气的时间, 定气法
-//	year 年
+//	year 年, 年初按照 Astronomy.CalendarReform 选择历法
 func (astro *Astronomy) SolarTerms(year int) ([]*JulianDayExtra, error) {
-	jd := DateToJulianDay(year, 1, 1, 0, 0, 0)
-	return astro.SolarTermsRange(jd, jd.AddYears(1))
+	reform := astro.calendarReform()
+	jd := reform.DateToJulianDay(year, 1, 1, 0, 0, 0)
+	return astro.SolarTermsRange(jd, reform.AddYears(jd, 1))
 }
@@ -0,0 +1,113 @@
+package astro
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FixStarCatalogFile swe的恒星表文件名
+const FixStarCatalogFile = "sefstars.txt"
+
+// LunarMansionStrings 二十八宿
+var LunarMansionStrings = [...]string{
+	"角", "亢", "氐", "房", "心", "尾", "箕", // 东方青龙
+	"斗", "牛", "女", "虚", "危", "室", "壁", // 北方玄武
+	"奎", "娄", "胃", "昴", "毕", "觜", "参", // 西方白虎
+	"井", "鬼", "柳", "星", "张", "翼", "轸", // 南方朱雀
+}
+
+// LunarMansionStars 二十八宿的距星, 与 LunarMansionStrings 一一对应
+// 使用swe的拜耳命名(以逗号开头), 可以通过 LoadFixStarCatalog 加载自定义的恒星表
+var LunarMansionStars = [...]string{
+	",alVir", ",kaVir", ",al-2Lib", ",piSco", ",siSco", ",mu-1Sco", ",gaSgr",
+	",phSgr", ",be-1Cap", ",epAqr", ",beAqr", ",alAqr", ",alPeg", ",gaPeg",
+	",zeAnd", ",beAri", ",35Ari", ",17Tau", ",epTau", ",laOri", ",zeOri",
+	",muGem", ",thCnc", ",deHya", ",alHya", ",up-1Hya", ",alCrt", ",gaCrv",
+}
+
+type StarProperties struct {
+	// 恒星的全称, 比如: "Spica,alVir"
+	Name string `json:"name"`
+	// 视星等
+	Magnitude float64 `json:"magnitude"`
+	// 黄道坐标
+	Ecliptic *EclipticCoordinates `json:"ecliptic"`
+	// 距离 单位是 AU, 没有视差数据的恒星为0
+	Distance float64 `json:"distance"`
+	// 黄经的速度 单位是 弧度/天
+	SpeedInLongitude float64 `json:"speed_in_longitude"`
+	// 黄纬的速度 单位是 弧度/天
+	SpeedInLatitude float64 `json:"speed_in_latitude"`
+	// 时角
+	HourAngle HourAngle `json:"hour_angle"`
+	// 赤道坐标
+	Equatorial *EquatorialCoordinates `json:"equatorial"`
+	// 地平坐标
+	Horizontal *HorizontalCoordinates `json:"horizontal"`
+}
+
+// LoadFixStarCatalog 重新加载自定义的恒星表 sefstars.txt
+// 恒星表需放在swe的星历表目录中(见 swe.SweInterface.EphePath), 替换文件后调用即可
+func (astro *Astronomy) LoadFixStarCatalog() error {
+	found := false
+	// 星历表目录可以是多个, 以 ; 或 : 分隔
+	for _, dir := range strings.FieldsFunc(astro.Swe.EphePath(), func(r rune) bool {
+		return r == ';' || r == filepath.ListSeparator
+	}) {
+		if _, err := os.Stat(filepath.Join(dir, FixStarCatalogFile)); err == nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("LoadFixStarCatalog: %s not found in %s", FixStarCatalogFile, astro.Swe.EphePath())
+	}
+
+	// 关闭后重新打开, swe才会重新读取恒星表, 在同一个锁内完成
+	astro.Swe.Reload()
+	return nil
+}
+
+// StarProperties 恒星的属性，包含黄道坐标、赤道坐标、地平坐标、星等
+// 时角、地平坐标的计算同 PlanetPropertiesWithObserver (修正), 含章动、大气折射
+//	name 恒星的名称, 见 swe.SweInterface.FixStar
+//	jdET 时间
+//	geo 观察者地理位置
+func (astro *Astronomy) StarProperties(name string, jdET *EphemerisTime, geo *GeographicCoordinates) (*StarProperties, error) {
+	// 当前黄道倾角、章动等参数
+	ecliptic, err := astro.EclipticProperties(jdET)
+	if err != nil {
+		return nil, fmt.Errorf("StarProperties Ecliptic: %w", err)
+	}
+
+	fullName, res, _, err := astro.Swe.FixStar(name, jdET.Value(), astro.simpleCalcFlags(jdET.DeltaT))
+	if err != nil {
+		return nil, fmt.Errorf("StarProperties FixStar: %w", err)
+	}
+
+	_, magnitude, err := astro.Swe.FixStarMag(name)
+	if err != nil {
+		return nil, fmt.Errorf("StarProperties FixStarMag: %w", err)
+	}
+
+	star := &StarProperties{
+		Name:      fullName,
+		Magnitude: magnitude,
+		Ecliptic: &EclipticCoordinates{
+			Longitude: res[0],
+			Latitude:  res[1],
+		},
+		Distance:         res[2],
+		SpeedInLongitude: res[3],
+		SpeedInLatitude:  res[4],
+	}
+
+	// 没有视差数据的恒星, 视为无穷远
+	distance := IfThenElse(star.Distance > 0, star.Distance, math.Inf(1)).(float64)
+	star.HourAngle, star.Equatorial, star.Horizontal = astro.observerCoordinates(star.Ecliptic, distance, ecliptic, jdET, geo, true)
+
+	return star, nil
+}
@@ -0,0 +1,133 @@
+package astro
+
+import (
+	"fmt"
+	"go-swe/src/swe"
+)
+
+type StationType int
+
+const (
+	// 留(顺转逆), 之后开始逆行
+	StationRetrograde StationType = iota
+	// 留(逆转顺), 之后开始顺行
+	StationDirect
+)
+
+var StationTypeStrings = [...]string{"留(顺转逆)", "留(逆转顺)"}
+
+// Station 行星的留, 即黄经速度为0的时刻
+type Station struct {
+	JdUT JulianDay   `json:"jd_ut"`
+	Type StationType `json:"type"`
+	// 留时的黄经
+	Longitude float64 `json:"longitude"`
+}
+
+// Ingress 天体经过某个黄经的时刻
+type Ingress struct {
+	JdUT JulianDay `json:"jd_ut"`
+	// 目标黄经的下标, 未指定目标黄经时即为星座 0~11
+	Index int `json:"index"`
+	// 目标黄经
+	Longitude float64 `json:"longitude"`
+	// 是否在逆行中经过
+	Retrograde bool `json:"retrograde"`
+}
+
+// planetSearchStep 搜索时的采样步长(天)
+// 需要小于两次留的最小间隔, 且每一步的黄经变化小于180°
+func planetSearchStep(planet swe.Planet) float64 {
+	switch planet {
+	case swe.Mercury:
+		return 2
+	case swe.Venus, swe.Mars:
+		return 5
+	case swe.Jupiter, swe.Saturn, swe.Uranus, swe.Neptune, swe.Pluto:
+		return 10
+	default:
+		return 1
+	}
+}
+
+// eclipticLongitudeFunc 天体黄经的 AngleFunc
+func (astro *Astronomy) eclipticLongitudeFunc(planet swe.Planet) AngleFunc {
+	return func(jdUT JulianDay) (float64, float64, error) {
+		props, err := astro.PlanetProperties(planet, astro.NewEphemerisTime(jdUT))
+		if err != nil {
+			return 0, 0, err
+		}
+		return props.Ecliptic.Longitude, props.SpeedInLongitude, nil
+	}
+}
+
+// Stations 时间范围内天体的留, 即黄经速度变号的时刻
+// 日、月不会逆行, 结果为空
+//	planet 天体
+//	startJdUT, endJdUT 时间范围 [startJdUT, endJdUT)
+func (astro *Astronomy) Stations(planet swe.Planet, startJdUT, endJdUT JulianDay) ([]*Station, error) {
+	angle := astro.eclipticLongitudeFunc(planet)
+
+	roots, err := FindRoots(startJdUT, endJdUT, planetSearchStep(planet), func(jdUT JulianDay) (float64, error) {
+		_, speed, err := angle(jdUT)
+		return speed, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Stations: %w", err)
+	}
+
+	var stations []*Station
+	for _, jd := range roots {
+		longitude, _, err := angle(jd)
+		if err != nil {
+			return nil, fmt.Errorf("Stations: %w", err)
+		}
+		// 留之后的速度决定是顺转逆还是逆转顺
+		_, speed, err := angle(jd.Add(searchPrecision * 10))
+		if err != nil {
+			return nil, fmt.Errorf("Stations: %w", err)
+		}
+		stations = append(stations, &Station{
+			JdUT:      jd,
+			Type:      IfThenElse(speed < 0, StationRetrograde, StationDirect).(StationType),
+			Longitude: longitude,
+		})
+	}
+
+	return stations, nil
+}
+
+// Ingresses 时间范围内天体经过指定黄经的时刻, 包括逆行时的经过, 按时间排序
+// 与 SolarEclipticLongitudesToTimes 不同, 不要求天体一直顺行
+//	planet 天体
+//	startJdUT, endJdUT 时间范围 [startJdUT, endJdUT)
+//	longitudes 目标黄经(弧度), 为空时为12星座的起点, 即每30°
+func (astro *Astronomy) Ingresses(planet swe.Planet, startJdUT, endJdUT JulianDay, longitudes ...float64) ([]*Ingress, error) {
+	if len(longitudes) == 0 {
+		for i := 0; i < 12; i++ {
+			longitudes = append(longitudes, ToRadians(float64(i)*30))
+		}
+	}
+
+	angle := astro.eclipticLongitudeFunc(planet)
+	crossings, err := FindAngleCrossings(startJdUT, endJdUT, planetSearchStep(planet), longitudes, angle)
+	if err != nil {
+		return nil, fmt.Errorf("Ingresses: %w", err)
+	}
+
+	var ingresses []*Ingress
+	for _, crossing := range crossings {
+		_, speed, err := angle(crossing.JdUT)
+		if err != nil {
+			return nil, fmt.Errorf("Ingresses: %w", err)
+		}
+		ingresses = append(ingresses, &Ingress{
+			JdUT:       crossing.JdUT,
+			Index:      crossing.Index,
+			Longitude:  longitudes[crossing.Index],
+			Retrograde: speed < 0,
+		})
+	}
+
+	return ingresses, nil
+}
@@ -26,13 +26,9 @@ type JulianDayExtra struct {
 }
 
 // TimeToJulianDay time 转为 儒略日 JulianDay
-// 带时区的时间会被自动转为UTC
+// time 的年月日时分秒(含时区)视为 DefaultCalendarReform 下的日期, 改历之前为儒略历, 与 ToTime 互逆
 func TimeToJulianDay(_time time.Time) JulianDay {
-	_time = _time.UTC()
-	_hour := float64(_time.Hour()) + float64(_time.Minute())/60. + float64(_time.Second()+_time.Nanosecond()/1e9)/3600.
-	jd, _ := swe.NewSwe().JulDay(_time.Year(), int(_time.Month()), _time.Day(), _hour, swe.Gregorian)
-
-	return JulianDay(jd)
+	return DefaultCalendarReform.TimeToJulianDay(_time)
 }
 
 // MakeJulianDayHours 将 时,分,秒 (只能为UTC) 转化为 儒略日的小数部分 JulianDay
@@ -50,21 +46,19 @@ func ExtractJulianDayHours(hours float64) (hour, minute int, second float64) {
 }
 
 // DateToJulianDay 年,月,日,时,分,秒 (只能为UTC) 转换为 儒略日 JulianDay
+// 日期按照 DefaultCalendarReform 选择历法, 改历之前为儒略历
 func DateToJulianDay(year, month, day, hour, minute int, second float64) JulianDay {
-	_hour := MakeJulianDayHours(hour, minute, second)
-
-	jd, _ := swe.NewSwe().JulDay(year, month, day, _hour, swe.Gregorian)
-
-	return JulianDay(jd)
+	return DefaultCalendarReform.DateToJulianDay(year, month, day, hour, minute, second)
 }
 
 // ExtractJulianDay 儒略日 JulianDay 转化为 年,月,日,时,分,秒 (UTC)
+// 日期按照 DefaultCalendarReform 选择历法, 改历之前为儒略历
 func ExtractJulianDay(jd JulianDay) (year, month, day, hour, minute int, second float64) {
-	year, month, day, hours, _ := swe.NewSwe().RevJul(float64(jd), swe.Gregorian)
-	hour, minute, second = ExtractJulianDayHours(hours)
+	year, month, day, hour, minute, second, _ = DefaultCalendarReform.ExtractJulianDay(jd)
 	return
 }
 
+// NewEphemerisTime 使用 DefaultDeltaTModel 计算ΔT, 指定模型见 Astronomy.NewEphemerisTime
 func NewEphemerisTime(jdUT JulianDay) *EphemerisTime {
 	return (&EphemerisTime{}).Update(jdUT)
 }
@@ -75,7 +69,7 @@ func (et *EphemerisTime) Value() float64 {
 
 func (et *EphemerisTime) Update(jdUT JulianDay) *EphemerisTime {
 	et.JdUT = jdUT
-	et.DeltaT = DeltaT(jdUT) //.DeltaT(float64(jdUT))
+	et.DeltaT = DefaultDeltaTModel.DeltaT(jdUT)
 	return et
 }
 
@@ -84,13 +78,12 @@ func NewJulianDayExtra(jdUT JulianDay, index int) *JulianDayExtra {
 }
 
 // ToTime Ephemeris time 天文历时 jd转为local的 time.Time, 此时jd的时区
+// 年月日为 DefaultCalendarReform 下的日期, 改历之前为儒略历, 与 TimeToJulianDay 互逆, 见 CalendarDate.Time
 func (jd JulianDay) ToTime(local *time.Location) time.Time {
-	if local == nil {
-		local = time.UTC
+	if date := jd.ToDate(local, DefaultCalendarReform); date.Calendar == swe.Julian {
+		return date.Time()
 	}
-	year, month, day, hour, minute, second := ExtractJulianDay(jd)
-	date := time.Date(year, time.Month(month), day, hour, minute, int(second), int((second-float64(int(second)))*1e9), time.UTC)
-	return date.In(local)
+	return jd.toProlepticTime(local)
 }
 
 // ToCST JD UT 转 CST
@@ -134,20 +127,16 @@ func (jd JulianDay) Add(delta float64) JulianDay {
 	return jd + JulianDay(delta)
 }
 
-// AddYears 增加N年，减少用负数
+// AddYears 增加N年，减少用负数, 按照 DefaultCalendarReform 选择历法
 // 注意：大部分日历操作类都存在这个问题 2000-02-29 + 1 year -> 2001-03-01
 func (jd JulianDay) AddYears(years int) JulianDay {
-	y, m, d, t, _ := swe.NewSwe().RevJul(float64(jd), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y+years, m, d, t, swe.Gregorian)
-	return JulianDay(_jd)
+	return DefaultCalendarReform.AddYears(jd, years)
 }
 
-// AddMonths 增加N月，减少用负数
+// AddMonths 增加N月，减少用负数, 按照 DefaultCalendarReform 选择历法
 // 注意：大部分日历操作类都存在这个问题 2000-03-31 - 1 month -> 2000-03-02
 func (jd JulianDay) AddMonths(months int) JulianDay {
-	y, m, d, t, _ := swe.NewSwe().RevJul(float64(jd), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y, m+months, d, t, swe.Gregorian)
-	return JulianDay(_jd)
+	return DefaultCalendarReform.AddMonths(jd, months)
 }
 
 // AddDays 增加N日，减少用负数
@@ -179,30 +168,26 @@ func (jdz JulianDayWithLocation) Midnight() JulianDayWithLocation {
 // Noon 正午
 // the nature day's 12:00
 func (jdz JulianDayWithLocation) Noon() JulianDayWithLocation {
-	y, m, d, _, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y, m, d, 12, swe.Gregorian)
-	return JulianDayWithLocation(_jd)
+	y, m, d, _, _, _ := ExtractJulianDay(JulianDay(jdz))
+	return JulianDayWithLocation(DateToJulianDay(y, m, d, 12, 0, 0))
 }
 
 // StartOfDay 今天的零点，午夜 00:00:00
 func (jdz JulianDayWithLocation) StartOfDay() JulianDayWithLocation {
-	y, m, d, _, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y, m, d, 0, swe.Gregorian)
-	return JulianDayWithLocation(_jd)
+	y, m, d, _, _, _ := ExtractJulianDay(JulianDay(jdz))
+	return JulianDayWithLocation(DateToJulianDay(y, m, d, 0, 0, 0))
 }
 
 // EndOfDay 今天的 23:59:59
 func (jdz JulianDayWithLocation) EndOfDay() JulianDayWithLocation {
-	y, m, d, _, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y, m, d, MakeJulianDayHours(23, 59, 59), swe.Gregorian)
-	return JulianDayWithLocation(_jd)
+	y, m, d, _, _, _ := ExtractJulianDay(JulianDay(jdz))
+	return JulianDayWithLocation(DateToJulianDay(y, m, d, 23, 59, 59))
 }
 
-// StartOfMonth 月初 XXXX-XX-01 00:00:00
+// StartOfMonth 月初 XXXX-XX-01 00:00:00, 按照 DefaultCalendarReform 选择历法
 func (jdz JulianDayWithLocation) StartOfMonth() JulianDayWithLocation {
-	y, m, _, _, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y, m, 1, 0, swe.Gregorian)
-	return JulianDayWithLocation(_jd)
+	y, m, _, _, _, _ := ExtractJulianDay(JulianDay(jdz))
+	return JulianDayWithLocation(DateToJulianDay(y, m, 1, 0, 0, 0))
 }
 
 // EndOfMonth 月尾 XXXX-XX-日 23:59:59 其中，日可能为：28,29,30,31
@@ -210,18 +195,16 @@ func (jdz JulianDayWithLocation) EndOfMonth() JulianDayWithLocation {
 	return jdz.StartOfMonth().AddMonths(1).AddSeconds(-1)
 }
 
-// StartOfYear 年初 XXXX-01-01 00:00:00
+// StartOfYear 年初 XXXX-01-01 00:00:00, 按照 DefaultCalendarReform 选择历法
 func (jdz JulianDayWithLocation) StartOfYear() JulianDayWithLocation {
-	y, _, _, _, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jdz, _ := swe.NewSwe().JulDay(y, 1, 1, 0, swe.Gregorian)
-	return JulianDayWithLocation(_jdz)
+	y, _, _, _, _, _ := ExtractJulianDay(JulianDay(jdz))
+	return JulianDayWithLocation(DateToJulianDay(y, 1, 1, 0, 0, 0))
 }
 
-// EndOfYear 年尾 XXXX-12-31 23:59:59
+// EndOfYear 年尾 XXXX-12-31 23:59:59, 按照 DefaultCalendarReform 选择历法
 func (jdz JulianDayWithLocation) EndOfYear() JulianDayWithLocation {
-	y, _, _, _, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jdz, _ := swe.NewSwe().JulDay(y, 12, 31, MakeJulianDayHours(23, 59, 59), swe.Gregorian)
-	return JulianDayWithLocation(_jdz)
+	y, _, _, _, _, _ := ExtractJulianDay(JulianDay(jdz))
+	return JulianDayWithLocation(DateToJulianDay(y, 12, 31, 23, 59, 59))
 }
 
 // Add 增加日期，float64 的生成规则和儒略日一致
@@ -230,20 +213,16 @@ func (jdz JulianDayWithLocation) Add(delta float64) JulianDayWithLocation {
 	return jdz + JulianDayWithLocation(delta)
 }
 
-// AddYears 增加N年，减少用负数
+// AddYears 增加N年，减少用负数, 按照 DefaultCalendarReform 选择历法
 // 注意：大部分日历操作类都存在这个问题 2000-02-29 + 1 year -> 2001-03-01
 func (jdz JulianDayWithLocation) AddYears(years int) JulianDayWithLocation {
-	y, m, d, t, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y+years, m, d, t, swe.Gregorian)
-	return JulianDayWithLocation(_jd)
+	return JulianDayWithLocation(DefaultCalendarReform.AddYears(JulianDay(jdz), years))
 }
 
-// AddMonths 增加N月，减少用负数
+// AddMonths 增加N月，减少用负数, 按照 DefaultCalendarReform 选择历法
 // 注意：大部分日历操作类都存在这个问题 2000-03-31 - 1 month -> 2000-03-02
 func (jdz JulianDayWithLocation) AddMonths(months int) JulianDayWithLocation {
-	y, m, d, t, _ := swe.NewSwe().RevJul(float64(jdz), swe.Gregorian)
-	_jd, _ := swe.NewSwe().JulDay(y, m+months, d, t, swe.Gregorian)
-	return JulianDayWithLocation(_jd)
+	return JulianDayWithLocation(DefaultCalendarReform.AddMonths(JulianDay(jdz), months))
 }
 
 // AddDays 增加N日，减少用负数
@@ -0,0 +1,236 @@
+package astro
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeScale 时间尺度
+type TimeScale int
+
+const (
+	// 协调世界时, 与TAI相差整数秒(闰秒)
+	UTC TimeScale = iota
+	// 世界时, 由地球自转决定, 即 JulianDay
+	UT1
+	// 国际原子时
+	TAI
+	// 地球时, 即天文历时(ET, TD), 即 EphemerisTime.Value()
+	TT
+	// 质心动力学时, 与TT的差小于2毫秒
+	TDB
+	// GPS时, 与TAI相差19秒
+	GPS
+)
+
+var TimeScaleStrings = [...]string{"UTC", "UT1", "TAI", "TT", "TDB", "GPS"}
+
+const (
+	// TT - TAI 单位是 秒
+	TTMinusTAI = 32.184
+	// TAI - GPS 单位是 秒
+	TAIMinusGPS = 19.
+	// 1972-01-01 UTC 的MJD, 之前的UTC没有闰秒表, 视为UT1
+	leapSecondsStartMJD = 41317.
+)
+
+func (ts TimeScale) String() string {
+	if ts < 0 || int(ts) >= len(TimeScaleStrings) {
+		return fmt.Sprintf("TimeScale(%d)", ts)
+	}
+	return TimeScaleStrings[ts]
+}
+
+// LeapSecond 闰秒表的一项, 从MJD(UTC)起, TAI-UTC的值
+type LeapSecond struct {
+	MJD         MJD     `json:"mjd"`
+	TAIMinusUTC float64 `json:"tai_minus_utc"`
+}
+
+// 闰秒表, 来自IERS的 Leap_Second.dat, 可以通过 LoadLeapSeconds 更新
+var leapSeconds = []LeapSecond{
+	{41317, 10}, {41499, 11}, {41683, 12}, {42048, 13}, {42413, 14}, {42778, 15},
+	{43144, 16}, {43509, 17}, {43874, 18}, {44239, 19}, {44786, 20}, {45151, 21},
+	{45516, 22}, {46247, 23}, {47161, 24}, {47892, 25}, {48257, 26}, {48804, 27},
+	{49169, 28}, {49534, 29}, {50083, 30}, {50630, 31}, {51179, 32}, {53736, 33},
+	{54832, 34}, {56109, 35}, {57204, 36}, {57754, 37},
+}
+var leapSecondsLock sync.RWMutex
+
+// LeapSeconds 当前的闰秒表
+func LeapSeconds() []LeapSecond {
+	leapSecondsLock.RLock()
+	defer leapSecondsLock.RUnlock()
+	return append([]LeapSecond{}, leapSeconds...)
+}
+
+// LoadLeapSeconds 从IERS的 Leap_Second.dat 格式的文件中加载闰秒表
+// 以#开头的为注释, 数据行为: MJD 日 月 年 TAI-UTC, 比如: 41317.0    1  1 1972       10
+//	path 文件路径
+func LoadLeapSeconds(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("LoadLeapSeconds: %w", err)
+	}
+	defer file.Close()
+
+	var table []LeapSecond
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 5 {
+			return fmt.Errorf("LoadLeapSeconds: invalid line %d: %s", line, text)
+		}
+		mjd, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return fmt.Errorf("LoadLeapSeconds: invalid MJD in line %d: %w", line, err)
+		}
+		seconds, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return fmt.Errorf("LoadLeapSeconds: invalid TAI-UTC in line %d: %w", line, err)
+		}
+		table = append(table, LeapSecond{MJD: MJD(mjd), TAIMinusUTC: seconds})
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("LoadLeapSeconds: %w", err)
+	}
+	if len(table) == 0 {
+		return fmt.Errorf("LoadLeapSeconds: no leap seconds in %s", path)
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		return table[i].MJD < table[j].MJD
+	})
+
+	leapSecondsLock.Lock()
+	leapSeconds = table
+	leapSecondsLock.Unlock()
+	return nil
+}
+
+// TAIMinusUTC 某UTC时刻的 TAI-UTC(秒), 1972年之前返回false
+//	jdUTC UTC的儒略日
+func TAIMinusUTC(jdUTC float64) (float64, bool) {
+	mjd := MJD(jdUTC - 2400000.5)
+	if mjd < leapSecondsStartMJD {
+		return 0, false
+	}
+
+	leapSecondsLock.RLock()
+	defer leapSecondsLock.RUnlock()
+
+	i := sort.Search(len(leapSeconds), func(i int) bool {
+		return leapSeconds[i].MJD > mjd
+	})
+	if i == 0 {
+		return 0, false
+	}
+	return leapSeconds[i-1].TAIMinusUTC, true
+}
+
+// TDBMinusTT TDB - TT(秒), 只取主要的周期项, 误差在几十微秒内
+//	jdTT TT的儒略日
+func TDBMinusTT(jdTT float64) float64 {
+	g := ToRadians(357.53 + 0.98560028*(jdTT-JD2000_OFFSET))
+	return 0.001657*math.Sin(g) + 0.000014*math.Sin(2*g)
+}
+
+// timeScaleDeltaT 时间尺度转换使用的ΔT(天)
+// 有闰秒的年代UT1与UTC相差不到0.9秒, 使用swe的ΔT, 与 swe.SweInterface.UTCToJD 一致; 之前的年代使用当前的ΔT模型
+//	jdUT 儒略日
+func (astro *Astronomy) timeScaleDeltaT(jdUT float64) float64 {
+	if MJD(jdUT-2400000.5) >= leapSecondsStartMJD {
+		return astro.Swe.DeltaT(jdUT)
+	}
+	return astro.DeltaT(JulianDay(jdUT))
+}
+
+// ToTT 任意时间尺度的儒略日转为TT
+// 1972年之前的UTC没有闰秒, 视为UT1
+//	jd 儒略日
+//	scale jd的时间尺度
+func (astro *Astronomy) ToTT(jd float64, scale TimeScale) float64 {
+	switch scale {
+	case UTC:
+		if taiMinusUTC, ok := TAIMinusUTC(jd); ok {
+			return jd + (taiMinusUTC+TTMinusTAI)/86400
+		}
+		return astro.ToTT(jd, UT1)
+	case UT1:
+		return jd + astro.timeScaleDeltaT(jd)
+	case TAI:
+		return jd + TTMinusTAI/86400
+	case GPS:
+		return jd + (TAIMinusGPS+TTMinusTAI)/86400
+	case TDB:
+		// TDB与TT的差很小, 以TDB代替TT计算即可
+		return jd - TDBMinusTT(jd)/86400
+	default:
+		return jd
+	}
+}
+
+// FromTT TT的儒略日转为任意时间尺度
+//	jdTT TT的儒略日
+//	scale 目标时间尺度
+func (astro *Astronomy) FromTT(jdTT float64, scale TimeScale) float64 {
+	switch scale {
+	case UTC:
+		// 以TAI-UTC估算UTC, 再以UTC查表修正, 闰秒附近最多差1秒, 迭代1次即可
+		jdTAI := jdTT - TTMinusTAI/86400
+		if taiMinusUTC, ok := TAIMinusUTC(jdTAI); ok {
+			jd := jdTAI - taiMinusUTC/86400
+			if taiMinusUTC, ok = TAIMinusUTC(jd); ok {
+				return jdTAI - taiMinusUTC/86400
+			}
+		}
+		return astro.FromTT(jdTT, UT1)
+	case UT1:
+		// ΔT变化很慢, 迭代1次即可
+		jd := jdTT - astro.timeScaleDeltaT(jdTT)
+		return jdTT - astro.timeScaleDeltaT(jd)
+	case TAI:
+		return jdTT - TTMinusTAI/86400
+	case GPS:
+		return jdTT - (TAIMinusGPS+TTMinusTAI)/86400
+	case TDB:
+		return jdTT + TDBMinusTT(jdTT)/86400
+	default:
+		return jdTT
+	}
+}
+
+// ConvertTimeScale 儒略日在时间尺度之间的转换, 以TT为中间量
+//	jd 儒略日
+//	from jd的时间尺度
+//	to 目标时间尺度
+func (astro *Astronomy) ConvertTimeScale(jd float64, from, to TimeScale) float64 {
+	if from == to {
+		return jd
+	}
+	return astro.FromTT(astro.ToTT(jd, from), to)
+}
+
+// TimeToEphemerisTime time.Time(UTC) 转为 EphemerisTime
+// 与 NewEphemerisTime(TimeToJulianDay(t)) 不同, 将t视为UTC, 通过闰秒表得到精确的TT, 再由ΔT得到UT1, 见 timeScaleDeltaT
+//	t 时间, 带时区的时间会被自动转为UTC
+func (astro *Astronomy) TimeToEphemerisTime(t time.Time) *EphemerisTime {
+	jdTT := astro.ToTT(float64(TimeToJulianDay(t)), UTC)
+	jdUT1 := astro.FromTT(jdTT, UT1)
+	return &EphemerisTime{
+		JdUT:   JulianDay(jdUT1),
+		DeltaT: jdTT - jdUT1,
+	}
+}
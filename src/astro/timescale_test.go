@@ -0,0 +1,103 @@
+package astro
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTAIMinusUTC(t *testing.T) {
+	tests := []struct {
+		name string
+		mjd  float64
+		want float64
+		ok   bool
+	}{
+		{name: "before 1972", mjd: 41316.9999, ok: false},
+		{name: "first entry", mjd: 41317, want: 10, ok: true},
+		{name: "before the second entry", mjd: 41498.9999, want: 10, ok: true},
+		{name: "second entry", mjd: 41499, want: 11, ok: true},
+		{name: "before the last entry", mjd: 57753.9999, want: 36, ok: true},
+		{name: "last entry", mjd: 57754, want: 37, ok: true},
+		{name: "after the last entry", mjd: 60000, want: 37, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TAIMinusUTC(tt.mjd + 2400000.5)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("TAIMinusUTC() = %f, %t, want %f, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLoadLeapSeconds(t *testing.T) {
+	saved := LeapSeconds()
+	defer func() {
+		leapSecondsLock.Lock()
+		leapSeconds = saved
+		leapSecondsLock.Unlock()
+	}()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	path := write("Leap_Second.dat", `#  Value of TAI-UTC in second valid beetween the initial value until
+#  the epoch given on the next line.
+#
+#  MJD        Date        TAI-UTC (s)
+#           day month year
+#  ---    --------------   ------
+#
+    41499.0    1  7 1972       11
+    41317.0    1  1 1972       10
+
+    41683.0    1  1 1973       12
+`)
+	if err := LoadLeapSeconds(path); err != nil {
+		t.Fatalf("LoadLeapSeconds() error = %v", err)
+	}
+
+	table := LeapSeconds()
+	if len(table) != 3 || table[0].MJD != 41317 || table[2].MJD != 41683 {
+		t.Fatalf("LeapSeconds() = %v, want 3 entries sorted by MJD", table)
+	}
+	tests := []struct {
+		mjd  float64
+		want float64
+	}{
+		{mjd: 41317, want: 10},
+		{mjd: 41498.9999, want: 10},
+		{mjd: 41499, want: 11},
+		{mjd: 41683, want: 12},
+		{mjd: 57754, want: 12},
+	}
+	for _, tt := range tests {
+		if got, ok := TAIMinusUTC(tt.mjd + 2400000.5); !ok || got != tt.want {
+			t.Errorf("TAIMinusUTC(MJD %f) = %f, %t, want %f", tt.mjd, got, ok, tt.want)
+		}
+	}
+
+	for name, content := range map[string]string{
+		"empty.dat":   "# no data\n",
+		"short.dat":   "41317.0    1  1 1972\n",
+		"invalid.dat": "41317.0    1  1 1972       ten\n",
+	} {
+		if err := LoadLeapSeconds(write(name, content)); err == nil {
+			t.Errorf("LoadLeapSeconds(%s) should return an error", name)
+		}
+	}
+	if got := LeapSeconds(); len(got) != 3 {
+		t.Errorf("a failed load should keep the table, got %d entries", len(got))
+	}
+	if err := LoadLeapSeconds(filepath.Join(dir, "missing.dat")); err == nil {
+		t.Errorf("LoadLeapSeconds() of a missing file should return an error")
+	}
+}
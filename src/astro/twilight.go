@@ -1,7 +1,9 @@
 package astro
 
 import (
+	"fmt"
 	"go-swe/src/swe"
+	"math"
 	"time"
 )
 
@@ -41,20 +43,36 @@ type SunTwilightAngles struct {
 	Astronomical, Nautical, Civil float64
 }
 
+// TwilightState 天体在某个高度角的 升/降 状态
+type TwilightState int
+
+const (
+	// TwilightNormal 正常的升/降
+	TwilightNormal TwilightState = iota
+	// TwilightAlwaysAbove 全天都在该高度角之上, 比如极昼
+	TwilightAlwaysAbove
+	// TwilightAlwaysBelow 全天都在该高度角之下, 比如极夜
+	TwilightAlwaysBelow
+)
+
 // TwilightTimes 天体的 升/降 时间
 type TwilightTimes struct {
-	// 升天, 降天
+	// 升天, 降天, State不是 TwilightNormal 时为0; RiseSetTransit 时不升或不落的那一个为0
 	Rise, Set JulianDay
+	// 升/降的状态
+	State TwilightState
 	// 上中天，下中天
 	Culmination, LowerCulmination JulianDay
 }
 
 // DawnDuskTimes 天体的 晨/暮 时间
 type DawnDuskTimes struct {
-	// 晨
+	// 晨, State不是 TwilightNormal 时为0
 	Dawn JulianDay
-	// 暮
+	// 暮, State不是 TwilightNormal 时为0
 	Dusk JulianDay
+	// 晨/暮的状态
+	State TwilightState
 }
 
 // SunTwilightTimes 太阳的 升/降/晨/暮 时间
@@ -88,14 +106,27 @@ func NewTwilightAngle() *TwilightAngle {
 }
 
 // Daylight 日照时长
-// 日落 - 升日
+// 日落 - 升日, 极昼为24小时, 极夜为0
 func (stt *SunTwilightTimes) Daylight() time.Duration {
+	switch stt.State {
+	case TwilightAlwaysAbove:
+		return 24 * time.Hour
+	case TwilightAlwaysBelow:
+		return 0
+	}
 	return time.Duration(float64(stt.Set-stt.Rise) * 86400 * 1e9)
 }
 
 // Night 夜晚时长，不同于自然夜（即天黑~天亮），而因为跨午夜，所以取今日00:00 ~ 23:59的时段
 // 即 (0:00（今天凌晨） ~ 天文晨光(今天天亮)) + (天文暮光(今天天黑) ~ 23:59（今天半夜）)   即 天文晨光 - 天文暮光 + 1
+// 太阳全天都在-18°之下为24小时, 全天都在-18°之上(比如高纬度的夏季)为0
 func (stt *SunTwilightTimes) Night() time.Duration {
+	switch stt.Astronomical.State {
+	case TwilightAlwaysAbove:
+		return 0
+	case TwilightAlwaysBelow:
+		return 24 * time.Hour
+	}
 	return time.Duration(float64(
 		//stt.Astronomical.Dawn - stt.Astronomical.Dawn.Midnight() +
 		//	(stt.Astronomical.Dusk.AddDays(1).Midnight() - stt.Astronomical.Dusk),
@@ -106,6 +137,7 @@ func (stt *SunTwilightTimes) Night() time.Duration {
 /**
  * 指定高度角，反推出当时的儒略日，因为1天内任意高度角有2个相同的，所以会分别返回东、西两个方位的时间
  * 如果高度角=90°或-90°是中天，上中天返回相同的2个时间，下中天返回当日和次日的时间
+ * 如果天体全天都在该高度角之上或之下，返回对应的状态，时间为0（中天除外）
  * lastPlanet 初步计算的天体属性
  * jdET 初步计算的时间
  * geo 观察者地理位置
@@ -119,9 +151,10 @@ func calcJulianDayByAltitude(
 	geo *GeographicCoordinates,
 	altitude float64,
 	withRevise bool,
-) (*[2]JulianDay, error) {
+) (*[2]JulianDay, TwilightState, error) {
 
 	var _ha HourAngle
+	var _state, state TwilightState
 	var err error
 	var _delta float64
 	var times = &[2]JulianDay{}
@@ -144,24 +177,33 @@ func calcJulianDayByAltitude(
 	times[1] = jdET.JdUT.Add(_delta)
 
 	// 计算第二次, 修正东
-	lastPlanet, err = astro.PlanetPropertiesWithObserver(lastPlanet.PlanetId, NewEphemerisTime(times[0]), geo, withRevise)
+	lastPlanet, err = astro.PlanetPropertiesWithObserver(lastPlanet.PlanetId, astro.NewEphemerisTime(times[0]), geo, withRevise)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	_ha = AltitudeToHourAngle(lastPlanet.Equatorial.Declination, geo.Latitude, altitude)
+	_ha, state = altitudeToHourAngle(lastPlanet.Equatorial.Declination, geo.Latitude, altitude)
 	_delta = RadiansMod180(float64(-_ha-lastPlanet.HourAngle)) / (Radian360 * fixValue)
 	times[0] = times[0].Add(_delta)
 
 	// 计算第二次, 修正西
-	lastPlanet, err = astro.PlanetPropertiesWithObserver(lastPlanet.PlanetId, NewEphemerisTime(times[1]), geo, withRevise)
+	lastPlanet, err = astro.PlanetPropertiesWithObserver(lastPlanet.PlanetId, astro.NewEphemerisTime(times[1]), geo, withRevise)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	_ha = AltitudeToHourAngle(lastPlanet.Equatorial.Declination, geo.Latitude, altitude)
+	_ha, _state = altitudeToHourAngle(lastPlanet.Equatorial.Declination, geo.Latitude, altitude)
 	_delta = RadiansMod180(float64(_ha-lastPlanet.HourAngle)) / (Radian360 * fixValue)
 	times[1] = times[1].Add(_delta)
 
-	return times, nil
+	// 东、西任一边无解, 都视为全天在该高度角之上或之下
+	if state == TwilightNormal {
+		state = _state
+	}
+	// 中天(±90°)必然会被限制, 不属于异常
+	if state != TwilightNormal && !FloatEqual(math.Abs(altitude), Radian90, 9) {
+		return &[2]JulianDay{}, state, nil
+	}
+
+	return times, TwilightNormal, nil
 }
 
 /**
@@ -171,14 +213,15 @@ func calcJulianDayByAltitude(
  * planetId 天体ID
  * altitude 带求值的高度角
  * withRevise 是否修正一些日光差，或者黄道章动
+ * 天体全天都在该高度角之上或之下时，时间为0，并返回对应的状态，见 TwilightState
  */
-func (astro *Astronomy) AltitudeToTimes(jdUT JulianDay, geo *GeographicCoordinates, planetId swe.Planet, altitude float64, withRevise bool) (*[2]JulianDay, error) {
-	jdET := NewEphemerisTime(jdUT)
+func (astro *Astronomy) AltitudeToTimes(jdUT JulianDay, geo *GeographicCoordinates, planetId swe.Planet, altitude float64, withRevise bool) (*[2]JulianDay, TwilightState, error) {
+	jdET := astro.NewEphemerisTime(jdUT)
 
 	// 天体属性
 	planet, err := astro.PlanetPropertiesWithObserver(planetId, jdET, geo, withRevise)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	return calcJulianDayByAltitude(astro, planet, jdET, geo, altitude, withRevise)
@@ -194,7 +237,7 @@ func (astro *Astronomy) AltitudeToTimes(jdUT JulianDay, geo *GeographicCoordinat
 func (astro *Astronomy) SunTwilight(jdUT JulianDay, geo *GeographicCoordinates, withRevise bool) (*SunTwilightTimes, error) {
 	// 查找最靠近当日中午的日上中天, mod2的第1参数为本地时角近似值
 	noonJdUT := jdUT.Add(-Mod2(float64(jdUT.ToJD2000())+geo.Longitude/Radian360, 1))
-	jdET := NewEphemerisTime(noonJdUT)
+	jdET := astro.NewEphemerisTime(noonJdUT)
 
 	angle := NewSunTwilightAngles()
 	sunTimes := &SunTwilightTimes{}
@@ -209,21 +252,21 @@ func (astro *Astronomy) SunTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 	}
 
 	// 上中天
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Culmination, withRevise)
+	times, _, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Culmination, withRevise)
 	if err != nil {
 		return nil, err
 	}
 	sunTimes.Culmination = times[0]
 
 	// 下中天
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, -angle.Culmination, withRevise)
+	times, _, err = calcJulianDayByAltitude(astro, planet, jdET, geo, -angle.Culmination, withRevise)
 	if err != nil {
 		return nil, err
 	}
 	sunTimes.LowerCulmination = times[0]
 
 	// 升/降
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.RiseSet, withRevise)
+	times, sunTimes.State, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.RiseSet, withRevise)
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +275,7 @@ func (astro *Astronomy) SunTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 	sunTimes.Set = times[1]
 
 	// 民用晨/暮
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Civil, withRevise)
+	times, sunTimes.Civil.State, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Civil, withRevise)
 	if err != nil {
 		return nil, err
 	}
@@ -241,7 +284,7 @@ func (astro *Astronomy) SunTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 	sunTimes.Civil.Dusk = times[1]
 
 	// 航海晨/暮
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Nautical, withRevise)
+	times, sunTimes.Nautical.State, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Nautical, withRevise)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +293,7 @@ func (astro *Astronomy) SunTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 	sunTimes.Nautical.Dusk = times[1]
 
 	// 天文晨/暮
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Astronomical, withRevise)
+	times, sunTimes.Astronomical.State, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Astronomical, withRevise)
 	if err != nil {
 		return nil, err
 	}
@@ -272,7 +315,7 @@ func (astro *Astronomy) MoonTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 
 	// 查找最靠近当日中午的月上中天, mod2的第1参数为本地时角近似值
 	moonJdUT := jdUT.Add(-Mod2(0.1726222+0.966136808032357*float64(jdUT.ToJD2000())-0.0366*deltaT+geo.Longitude/Radian360, 1))
-	jdET := NewEphemerisTime(moonJdUT)
+	jdET := astro.NewEphemerisTime(moonJdUT)
 
 	angle := NewTwilightAngle()
 	moonTimes := &TwilightTimes{}
@@ -289,21 +332,21 @@ func (astro *Astronomy) MoonTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 	angle.RiseSet = 0.7275*EquatorialRadius/planet.DistanceAsKilometer() - 34*60/DegreeSecondsPerRadian
 
 	// 上中天
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Culmination, withRevise)
+	times, _, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.Culmination, withRevise)
 	if err != nil {
 		return nil, err
 	}
 	moonTimes.Culmination = times[0]
 
 	// 下中天
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, -angle.Culmination, withRevise)
+	times, _, err = calcJulianDayByAltitude(astro, planet, jdET, geo, -angle.Culmination, withRevise)
 	if err != nil {
 		return nil, err
 	}
 	moonTimes.LowerCulmination = times[0]
 
 	// 升/降
-	times, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.RiseSet, withRevise)
+	times, moonTimes.State, err = calcJulianDayByAltitude(astro, planet, jdET, geo, angle.RiseSet, withRevise)
 	if err != nil {
 		return nil, err
 	}
@@ -313,3 +356,133 @@ func (astro *Astronomy) MoonTwilight(jdUT JulianDay, geo *GeographicCoordinates,
 
 	return moonTimes, nil
 }
+
+// RiseSetOptions 计算升/降/中天时的选项
+type RiseSetOptions struct {
+	// 以天体圆面的中心计算升/降, 默认以圆面的上边缘计算
+	DiscCenter bool
+	// 不计算大气折射
+	NoRefraction bool
+	// 大气压(hPa), 为0时按照观察者的海拔估算
+	Pressure float64
+	// 气温(°C), 0即为0°C; NewRiseSetOptions 为15°C, 零值的 RiseSetOptions{} 按照0°C计算大气折射
+	Temperature float64
+	// 观察者的海拔(米)
+	Altitude float64
+	// 当地地平线的高度(弧度), 比如被山体遮挡时为正数
+	HorizonHeight float64
+}
+
+// NewRiseSetOptions 默认的选项: 以圆面上边缘计算, 气温15°C, 大气压按照海拔估算
+func NewRiseSetOptions() *RiseSetOptions {
+	return &RiseSetOptions{
+		Temperature: 15,
+	}
+}
+
+// RiseSetTransit 天体的升/降/上中天/下中天时间, 使用swe_rise_trans计算, 支持所有的天体
+//	planetId 天体ID
+//	jdUT 起始时间, 返回该时间之后的第一次升/降/中天, 比如传入当地0点的JdUT, 则返回当日的时间
+//	geo 观察者地理位置
+//	opts 选项, nil则使用 NewRiseSetOptions
+func (astro *Astronomy) RiseSetTransit(planetId swe.Planet, jdUT JulianDay, geo *GeographicCoordinates, opts *RiseSetOptions) (*TwilightTimes, error) {
+	return astro.riseSetTransit(planetId, "", jdUT, geo, opts)
+}
+
+// StarRiseSetTransit 恒星的升/降/上中天/下中天时间
+//	star 恒星的名称, 见swe的sefstars.txt, 比如: "Sirius", ",alVir"
+//	jdUT 起始时间, 返回该时间之后的第一次升/降/中天
+//	geo 观察者地理位置
+//	opts 选项, nil则使用 NewRiseSetOptions
+func (astro *Astronomy) StarRiseSetTransit(star string, jdUT JulianDay, geo *GeographicCoordinates, opts *RiseSetOptions) (*TwilightTimes, error) {
+	return astro.riseSetTransit(0, star, jdUT, geo, opts)
+}
+
+func (astro *Astronomy) riseSetTransit(planetId swe.Planet, star string, jdUT JulianDay, geo *GeographicCoordinates, opts *RiseSetOptions) (*TwilightTimes, error) {
+	if opts == nil {
+		opts = NewRiseSetOptions()
+	}
+
+	flags := &swe.RiseTransFlags{Flags: swe.FlagEphSwiss}
+	flags.SetDeltaT(astro.NewEphemerisTime(jdUT).DeltaT)
+
+	geoLoc := &swe.GeoLoc{
+		Long: ToDegrees(geo.Longitude),
+		Lat:  ToDegrees(geo.Latitude),
+		Alt:  opts.Altitude,
+	}
+
+	var bits swe.RiseTransMode
+	if opts.DiscCenter {
+		bits |= swe.BitDiscCenter
+	}
+	if opts.NoRefraction {
+		bits |= swe.BitNoRefraction
+	}
+
+	calc := func(rsmi swe.RiseTransMode) (JulianDay, bool, error) {
+		var tret float64
+		var circumpolar bool
+		var err error
+		if opts.HorizonHeight != 0 {
+			tret, circumpolar, err = astro.Swe.RiseTransTrueHor(float64(jdUT), planetId, star, flags, rsmi|bits, geoLoc, opts.Pressure, opts.Temperature, ToDegrees(opts.HorizonHeight))
+		} else {
+			tret, circumpolar, err = astro.Swe.RiseTrans(float64(jdUT), planetId, star, flags, rsmi|bits, geoLoc, opts.Pressure, opts.Temperature)
+		}
+		return JulianDay(tret), circumpolar, err
+	}
+
+	times := &TwilightTimes{}
+	var riseCircumpolar, setCircumpolar bool
+	var err error
+
+	if times.Rise, riseCircumpolar, err = calc(swe.CalcRise); err != nil {
+		return nil, fmt.Errorf("RiseSetTransit Rise: %w", err)
+	}
+	if times.Set, setCircumpolar, err = calc(swe.CalcSet); err != nil {
+		return nil, fmt.Errorf("RiseSetTransit Set: %w", err)
+	}
+	if times.Culmination, _, err = calc(swe.CalcMTransit); err != nil {
+		return nil, fmt.Errorf("RiseSetTransit Culmination: %w", err)
+	}
+	if times.LowerCulmination, _, err = calc(swe.CalcITransit); err != nil {
+		return nil, fmt.Errorf("RiseSetTransit LowerCulmination: %w", err)
+	}
+
+	// 不升或不落, 只清除不存在的那一个
+	if riseCircumpolar {
+		times.Rise = 0
+	}
+	if setCircumpolar {
+		times.Set = 0
+	}
+	// 既不升也不落时, 才是全天在地平线之上或之下
+	if riseCircumpolar && setCircumpolar {
+		if times.State, err = astro.circumpolarState(planetId, star, jdUT, geo); err != nil {
+			return nil, fmt.Errorf("RiseSetTransit Circumpolar: %w", err)
+		}
+	}
+
+	return times, nil
+}
+
+// circumpolarState 天体不升或不落时, 判断天体是全天在地平线之上还是之下
+// 此时天体的赤纬与观察者的纬度同号(在同一个半球)则全天在地平线之上
+func (astro *Astronomy) circumpolarState(planetId swe.Planet, star string, jdUT JulianDay, geo *GeographicCoordinates) (TwilightState, error) {
+	var declination float64
+	if star != "" {
+		_star, err := astro.StarProperties(star, astro.NewEphemerisTime(jdUT), geo)
+		if err != nil {
+			return 0, err
+		}
+		declination = _star.Equatorial.Declination
+	} else {
+		planet, err := astro.PlanetPropertiesWithObserver(planetId, astro.NewEphemerisTime(jdUT), geo, false)
+		if err != nil {
+			return 0, err
+		}
+		declination = planet.Equatorial.Declination
+	}
+
+	return IfThenElse(SameSign(declination, geo.Latitude), TwilightAlwaysAbove, TwilightAlwaysBelow).(TwilightState), nil
+}
@@ -0,0 +1,227 @@
+package chart
+
+import (
+	"go-swe/src/astro"
+	"go-swe/src/swe"
+	"math"
+	"sort"
+)
+
+type AspectType int
+
+const (
+	// 主要相位
+	Conjunction AspectType = iota
+	Sextile
+	Square
+	Trine
+	Opposition
+	// 次要相位
+	SemiSextile
+	SemiSquare
+	Quintile
+	Sesquiquadrate
+	BiQuintile
+	Quincunx
+)
+
+// AspectDefinition 相位的定义
+type AspectDefinition struct {
+	Type AspectType `json:"type"`
+	Name string     `json:"name"`
+	// 相位的角度 单位 度
+	Angle float64 `json:"angle"`
+	// 容许度 单位 度
+	Orb float64 `json:"orb"`
+}
+
+// MajorAspects 主要相位, 以及默认的容许度
+var MajorAspects = []*AspectDefinition{
+	{Type: Conjunction, Name: "Conjunction", Angle: 0, Orb: 8},
+	{Type: Sextile, Name: "Sextile", Angle: 60, Orb: 4},
+	{Type: Square, Name: "Square", Angle: 90, Orb: 6},
+	{Type: Trine, Name: "Trine", Angle: 120, Orb: 6},
+	{Type: Opposition, Name: "Opposition", Angle: 180, Orb: 8},
+}
+
+// MinorAspects 次要相位, 以及默认的容许度
+var MinorAspects = []*AspectDefinition{
+	{Type: SemiSextile, Name: "Semi-sextile", Angle: 30, Orb: 2},
+	{Type: SemiSquare, Name: "Semi-square", Angle: 45, Orb: 2},
+	{Type: Quintile, Name: "Quintile", Angle: 72, Orb: 2},
+	{Type: Sesquiquadrate, Name: "Sesquiquadrate", Angle: 135, Orb: 2},
+	{Type: BiQuintile, Name: "Bi-quintile", Angle: 144, Orb: 2},
+	{Type: Quincunx, Name: "Quincunx", Angle: 150, Orb: 3},
+}
+
+// AspectOptions 相位的计算参数
+type AspectOptions struct {
+	// 需要检测的相位, 为空时使用 MajorAspects
+	Aspects []*AspectDefinition `json:"aspects"`
+	// 是否附加 MinorAspects, 仅在 Aspects 为空时有效
+	Minor bool `json:"minor"`
+	// 天体的容许度系数, 比如日月可以设置为1.25, 未设置的天体为1
+	// 两个天体的相位, 使用两者中较大的系数乘以相位的容许度
+	OrbFactors map[swe.Planet]float64 `json:"orb_factors"`
+}
+
+// Aspect 两个天体之间的相位
+type Aspect struct {
+	First  swe.Planet `json:"first"`
+	Second swe.Planet `json:"second"`
+
+	Type AspectType `json:"type"`
+	Name string     `json:"name"`
+	// 相位的角度
+	Angle float64 `json:"angle"`
+	// 两个天体的黄经之差 0° ~ 180°
+	Separation float64 `json:"separation"`
+	// 与精确相位的偏差 即 Separation - Angle
+	Orb float64 `json:"orb"`
+	// 入相位(偏差在变小)为true, 出相位为false
+	Applying bool `json:"applying"`
+}
+
+// AspectGrid 相位表, Cells[i][j]为 Rows[i] 与 Columns[j] 的相位, 无相位时为nil
+// 同一星盘的相位表只填充上三角(j > i)
+type AspectGrid struct {
+	Rows    []swe.Planet `json:"rows"`
+	Columns []swe.Planet `json:"columns"`
+	Cells   [][]*Aspect  `json:"cells"`
+	// 所有的相位, 按照偏差的绝对值从小到大
+	Aspects []*Aspect `json:"aspects"`
+}
+
+// BodyPositionFromProperties 由 astro.PlanetProperties(弧度) 转为 BodyPosition(度), 不含宫位
+func BodyPositionFromProperties(p *astro.PlanetProperties) *BodyPosition {
+	longitude := degreesMod360(astro.ToDegrees(p.Ecliptic.Longitude))
+	sign, signDegree := SignOf(longitude)
+	speed := astro.ToDegrees(p.SpeedInLongitude)
+	return &BodyPosition{
+		Planet:     p.PlanetId,
+		Name:       p.PlanetId.String(),
+		Longitude:  longitude,
+		Latitude:   astro.ToDegrees(p.Ecliptic.Latitude),
+		Distance:   p.Distance,
+		Speed:      speed,
+		Retrograde: speed < 0,
+		Sign:       sign,
+		SignName:   SignStrings[sign],
+		SignDegree: signDegree,
+	}
+}
+
+func (opts *AspectOptions) definitions() []*AspectDefinition {
+	if opts != nil && len(opts.Aspects) > 0 {
+		return opts.Aspects
+	}
+	if opts != nil && opts.Minor {
+		return append(append([]*AspectDefinition{}, MajorAspects...), MinorAspects...)
+	}
+	return MajorAspects
+}
+
+func (opts *AspectOptions) orbFactor(planet swe.Planet) float64 {
+	if opts == nil || opts.OrbFactors == nil {
+		return 1
+	}
+	if factor, ok := opts.OrbFactors[planet]; ok {
+		return factor
+	}
+	return 1
+}
+
+// FindAspect 两个天体之间的相位, 没有相位时返回nil
+// 满足多个相位时(容许度设置过大), 取偏差最小的
+func FindAspect(a, b *BodyPosition, opts *AspectOptions) *Aspect {
+	// b相对a的黄经差 (-180°, 180°]
+	delta := degreesMod360(b.Longitude - a.Longitude)
+	if delta > 180 {
+		delta -= 360
+	}
+	separation := math.Abs(delta)
+	// 黄经差的绝对值的变化速度
+	separationSpeed := (b.Speed - a.Speed) * astro.IfThenElse(delta < 0, -1., 1.).(float64)
+
+	factor := math.Max(opts.orbFactor(a.Planet), opts.orbFactor(b.Planet))
+
+	var aspect *Aspect
+	for _, def := range opts.definitions() {
+		orb := separation - def.Angle
+		if math.Abs(orb) > def.Orb*factor {
+			continue
+		}
+		if aspect != nil && math.Abs(aspect.Orb) <= math.Abs(orb) {
+			continue
+		}
+		aspect = &Aspect{
+			First:      a.Planet,
+			Second:     b.Planet,
+			Type:       def.Type,
+			Name:       def.Name,
+			Angle:      def.Angle,
+			Separation: separation,
+			Orb:        orb,
+			// 偏差的绝对值在变小, 即为入相位
+			Applying: orb*separationSpeed < 0 || (orb == 0 && separationSpeed == 0),
+		}
+	}
+	return aspect
+}
+
+func newAspectGrid(rows, columns []*BodyPosition, same bool, opts *AspectOptions) *AspectGrid {
+	grid := &AspectGrid{
+		Cells:   make([][]*Aspect, len(rows)),
+		Aspects: []*Aspect{},
+	}
+	for _, body := range rows {
+		grid.Rows = append(grid.Rows, body.Planet)
+	}
+	for _, body := range columns {
+		grid.Columns = append(grid.Columns, body.Planet)
+	}
+
+	for i, a := range rows {
+		grid.Cells[i] = make([]*Aspect, len(columns))
+		for j, b := range columns {
+			// 同一星盘只计算上三角
+			if same && j <= i {
+				continue
+			}
+			if aspect := FindAspect(a, b, opts); aspect != nil {
+				grid.Cells[i][j] = aspect
+				grid.Aspects = append(grid.Aspects, aspect)
+			}
+		}
+	}
+
+	sort.SliceStable(grid.Aspects, func(i, j int) bool {
+		return math.Abs(grid.Aspects[i].Orb) < math.Abs(grid.Aspects[j].Orb)
+	})
+	return grid
+}
+
+// Aspects 同一组天体之间的相位表
+//	bodies 天体的位置
+//	opts 为nil时使用 MajorAspects 和默认的容许度
+func Aspects(bodies []*BodyPosition, opts *AspectOptions) *AspectGrid {
+	return newAspectGrid(bodies, bodies, true, opts)
+}
+
+// SynastryAspects 两组天体之间(合盘)的相位表, 行为inner, 列为outer
+//	inner 第一个星盘的天体位置
+//	outer 第二个星盘的天体位置
+//	opts 为nil时使用 MajorAspects 和默认的容许度
+func SynastryAspects(inner, outer []*BodyPosition, opts *AspectOptions) *AspectGrid {
+	return newAspectGrid(inner, outer, false, opts)
+}
+
+// Aspects 本命盘内各天体之间的相位表
+func (natal *Natal) Aspects(opts *AspectOptions) *AspectGrid {
+	return Aspects(natal.Bodies, opts)
+}
+
+// Synastry 与另一个本命盘的合盘相位表
+func (natal *Natal) Synastry(other *Natal, opts *AspectOptions) *AspectGrid {
+	return SynastryAspects(natal.Bodies, other.Bodies, opts)
+}
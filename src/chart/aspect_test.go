@@ -0,0 +1,149 @@
+package chart
+
+import (
+	"go-swe/src/swe"
+	"math"
+	"testing"
+)
+
+func TestFindAspect(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     *BodyPosition
+		opts     *AspectOptions
+		want     AspectType
+		none     bool
+		orb      float64
+		applying bool
+	}{
+		{
+			name:     "opposition applying before the wrap",
+			a:        &BodyPosition{Planet: swe.Sun, Longitude: 0, Speed: 0},
+			b:        &BodyPosition{Planet: swe.Mars, Longitude: 178, Speed: 1},
+			want:     Opposition,
+			orb:      -2,
+			applying: true,
+		},
+		{
+			name:     "opposition separating after the wrap",
+			a:        &BodyPosition{Planet: swe.Sun, Longitude: 0, Speed: 0},
+			b:        &BodyPosition{Planet: swe.Mars, Longitude: 182, Speed: 1},
+			want:     Opposition,
+			orb:      -2,
+			applying: false,
+		},
+		{
+			name:     "conjunction across 0° separating",
+			a:        &BodyPosition{Planet: swe.Sun, Longitude: 358, Speed: 0},
+			b:        &BodyPosition{Planet: swe.Mars, Longitude: 2, Speed: 1},
+			want:     Conjunction,
+			orb:      4,
+			applying: false,
+		},
+		{
+			name:     "conjunction across 0° applying",
+			a:        &BodyPosition{Planet: swe.Sun, Longitude: 358, Speed: 1},
+			b:        &BodyPosition{Planet: swe.Mars, Longitude: 2, Speed: 0},
+			want:     Conjunction,
+			orb:      4,
+			applying: true,
+		},
+		{
+			name: "orb exactly at the limit",
+			a:    &BodyPosition{Planet: swe.Sun, Longitude: 10},
+			b:    &BodyPosition{Planet: swe.Mars, Longitude: 74},
+			want: Sextile,
+			orb:  4,
+		},
+		{
+			name: "orb just outside the limit",
+			a:    &BodyPosition{Planet: swe.Sun, Longitude: 10},
+			b:    &BodyPosition{Planet: swe.Mars, Longitude: 74.001},
+			none: true,
+		},
+		{
+			name: "orb factor of the first body",
+			a:    &BodyPosition{Planet: swe.Sun, Longitude: 0},
+			b:    &BodyPosition{Planet: swe.Mars, Longitude: 65},
+			opts: &AspectOptions{OrbFactors: map[swe.Planet]float64{swe.Sun: 1.5}},
+			want: Sextile,
+			orb:  5,
+		},
+		{
+			name: "orb factor of the second body",
+			a:    &BodyPosition{Planet: swe.Mars, Longitude: 0},
+			b:    &BodyPosition{Planet: swe.Sun, Longitude: 65},
+			opts: &AspectOptions{OrbFactors: map[swe.Planet]float64{swe.Sun: 1.5}},
+			want: Sextile,
+			orb:  5,
+		},
+		{
+			name: "orb factor uses the larger one",
+			a:    &BodyPosition{Planet: swe.Sun, Longitude: 0},
+			b:    &BodyPosition{Planet: swe.Moon, Longitude: 65},
+			opts: &AspectOptions{OrbFactors: map[swe.Planet]float64{swe.Sun: 0.5, swe.Moon: 1.5}},
+			want: Sextile,
+			orb:  5,
+		},
+		{
+			name: "no orb factor",
+			a:    &BodyPosition{Planet: swe.Sun, Longitude: 0},
+			b:    &BodyPosition{Planet: swe.Moon, Longitude: 65},
+			none: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aspect := FindAspect(tt.a, tt.b, tt.opts)
+			if tt.none {
+				if aspect != nil {
+					t.Fatalf("FindAspect() = %s, want nil", aspect.Name)
+				}
+				return
+			}
+			if aspect == nil {
+				t.Fatalf("FindAspect() = nil, want %d", tt.want)
+			}
+			if aspect.Type != tt.want {
+				t.Errorf("Type = %d, want %d", aspect.Type, tt.want)
+			}
+			if math.Abs(aspect.Orb-tt.orb) > 1e-9 {
+				t.Errorf("Orb = %f, want %f", aspect.Orb, tt.orb)
+			}
+			if aspect.Applying != tt.applying {
+				t.Errorf("Applying = %t, want %t", aspect.Applying, tt.applying)
+			}
+		})
+	}
+}
+
+func TestNewAspectGrid(t *testing.T) {
+	bodies := []*BodyPosition{
+		{Planet: swe.Sun, Longitude: 0},
+		{Planet: swe.Moon, Longitude: 91},
+		{Planet: swe.Mars, Longitude: 180.5},
+	}
+
+	grid := newAspectGrid(bodies, bodies, true, nil)
+
+	for i := range bodies {
+		for j := 0; j <= i; j++ {
+			if grid.Cells[i][j] != nil {
+				t.Errorf("Cells[%d][%d] should be empty for the same chart", i, j)
+			}
+		}
+	}
+	// Sun-Moon 方形 1°, Sun-Mars 对冲 -0.5°, Moon-Mars 方形 -0.5°
+	if len(grid.Aspects) != 3 {
+		t.Fatalf("len(Aspects) = %d, want 3", len(grid.Aspects))
+	}
+	for i := 1; i < len(grid.Aspects); i++ {
+		if math.Abs(grid.Aspects[i-1].Orb) > math.Abs(grid.Aspects[i].Orb) {
+			t.Errorf("Aspects are not sorted by orb: %f before %f", grid.Aspects[i-1].Orb, grid.Aspects[i].Orb)
+		}
+	}
+	if grid.Cells[0][1] == nil || grid.Cells[0][1].Type != Square {
+		t.Errorf("Cells[0][1] should be a square")
+	}
+}
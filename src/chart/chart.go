@@ -0,0 +1,254 @@
+package chart
+
+import (
+	"fmt"
+	"go-swe/src/astro"
+	"go-swe/src/swe"
+	"math"
+	"time"
+)
+
+// SignStrings 黄道十二宫(星座), 每宫30°, 白羊宫起于0°
+var SignStrings = [...]string{
+	"Aries", "Taurus", "Gemini", "Cancer", "Leo", "Virgo",
+	"Libra", "Scorpio", "Sagittarius", "Capricorn", "Aquarius", "Pisces",
+}
+
+// DefaultBodies 默认计算的天体: 日月、八大行星(含冥王星)、真月交点
+// 凯龙星等小行星需要星历表文件(比如 seas_18.se1), 需在 NatalOptions.Bodies 中指定
+var DefaultBodies = []swe.Planet{
+	swe.Sun, swe.Moon, swe.Mercury, swe.Venus, swe.Mars,
+	swe.Jupiter, swe.Saturn, swe.Uranus, swe.Neptune, swe.Pluto,
+	swe.TrueNode,
+}
+
+// Chart 星盘的计算, 本包的角度单位均为 度
+type Chart struct {
+	Astronomy *astro.Astronomy
+}
+
+// NatalOptions 本命盘的参数
+type NatalOptions struct {
+	// 出生时间, 按照 Chart.Astronomy 的改历转为儒略日
+	Time time.Time `json:"time"`
+	// 出生时间的儒略日(UT), 不为0时代替 Time, 可以表示 time.Time 无法表示的儒略历日期
+	JdUT astro.JulianDay `json:"jd_ut"`
+	// 经度 西 -180° ~ 东 180°
+	Longitude float64 `json:"longitude"`
+	// 纬度 南 -90° ~ 北 90°
+	Latitude float64 `json:"latitude"`
+	// 宫位制, 比如: swe.Placidus
+	HouseSystem swe.HSys `json:"house_system"`
+	// 是否使用恒星黄道, 为false时为回归黄道
+	Sidereal bool `json:"sidereal"`
+	// 恒星黄道的岁差模式, Sidereal为true时有效
+	Ayanamsa swe.Ayanamsa `json:"ayanamsa"`
+	// 需要计算的天体, 为空时使用 DefaultBodies
+	Bodies []swe.Planet `json:"bodies"`
+}
+
+// BodyPosition 天体在星盘中的位置
+type BodyPosition struct {
+	Planet swe.Planet `json:"planet"`
+	Name   string     `json:"name"`
+	// 黄经 0° ~ 360°
+	Longitude float64 `json:"longitude"`
+	// 黄纬
+	Latitude float64 `json:"latitude"`
+	// 距离 单位是 AU
+	Distance float64 `json:"distance"`
+	// 黄经的速度 单位是 度/天
+	Speed float64 `json:"speed"`
+	// 是否逆行, 即黄经的速度为负
+	Retrograde bool `json:"retrograde"`
+	// 所在的星座 0~11, 见 SignStrings
+	Sign     int    `json:"sign"`
+	SignName string `json:"sign_name"`
+	// 在星座内的度数 0° ~ 30°
+	SignDegree float64 `json:"sign_degree"`
+	// 所在的宫位 [1, 13), 小数部分为在宫内的位置, 见 swe.SweInterface.HousePos
+	// 无法计算宫位时为0, 见 HouseError
+	House      float64 `json:"house"`
+	HouseError string  `json:"house_error,omitempty"`
+}
+
+// UnavailableBody 无法计算的天体, 以及原因
+type UnavailableBody struct {
+	Planet swe.Planet `json:"planet"`
+	Error  string     `json:"error"`
+}
+
+// Angles 星盘的四轴等
+type Angles struct {
+	// 上升点
+	Asc float64 `json:"asc"`
+	// 天顶
+	MC float64 `json:"mc"`
+	// 恒星时(赤经)
+	ARMC float64 `json:"armc"`
+	// 宿命点
+	Vertex float64 `json:"vertex"`
+}
+
+// Natal 本命盘
+type Natal struct {
+	JdUT   astro.JulianDay `json:"jd_ut"`
+	DeltaT float64         `json:"delta_t"`
+
+	HouseSystem     swe.HSys `json:"house_system"`
+	HouseSystemName string   `json:"house_system_name"`
+	// 宫位制无法计算时(比如极圈内的 Placidus、Koch)的原因, 此时 HouseSystem 为 swe.Porphyrius
+	HouseSystemError string `json:"house_system_error,omitempty"`
+
+	Sidereal bool         `json:"sidereal"`
+	Ayanamsa swe.Ayanamsa `json:"ayanamsa"`
+	// 岁差的值, 回归黄道时为0
+	AyanamsaValue float64 `json:"ayanamsa_value"`
+
+	Bodies []*BodyPosition `json:"bodies"`
+	// 无法计算的天体(比如缺少星历表文件), 不影响其它天体
+	Unavailable []*UnavailableBody `json:"unavailable,omitempty"`
+	// 12宫的宫头, Cusps[0]为第1宫
+	Cusps  [12]float64 `json:"cusps"`
+	Angles *Angles     `json:"angles"`
+}
+
+func NewChart(astronomy *astro.Astronomy) *Chart {
+	return &Chart{Astronomy: astronomy}
+}
+
+// SignOf 黄经所在的星座, 以及在星座内的度数
+//	longitude 黄经 单位 度
+func SignOf(longitude float64) (sign int, degree float64) {
+	longitude = degreesMod360(longitude)
+	sign = int(longitude / 30)
+	return sign, longitude - float64(sign)*30
+}
+
+func degreesMod360(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	// 极小的负数 + 360 会舍入为 360
+	if deg >= 360 {
+		deg = 0
+	}
+	return deg
+}
+
+// Natal 本命盘: 各天体的位置、星座、宫位, 以及宫头和四轴
+// 无法计算的天体会被跳过, 见 Natal.Unavailable
+// 宫位由回归黄道的坐标计算, 与黄道的选择无关
+// 宫位制无法计算时改用 swe.Porphyrius, 同 swe_houses, 见 Natal.HouseSystemError
+// 不支持36个扇区的 swe.Gauquelin
+func (chart *Chart) Natal(opts *NatalOptions) (*Natal, error) {
+	bodies := opts.Bodies
+	if len(bodies) == 0 {
+		bodies = DefaultBodies
+	}
+	hsys := opts.HouseSystem
+	if hsys == 0 {
+		hsys = swe.Placidus
+	}
+	if hsys == swe.Gauquelin || hsys == 'g' {
+		return nil, fmt.Errorf("Natal: Gauquelin sectors are not supported")
+	}
+
+	jdUT := opts.JdUT
+	if jdUT == 0 {
+		jdUT = chart.Astronomy.TimeToJulianDay(opts.Time)
+	}
+	jdET := chart.Astronomy.NewEphemerisTime(jdUT)
+	_swe := chart.Astronomy.Swe
+
+	natal := &Natal{
+		JdUT:        jdUT,
+		DeltaT:      jdET.DeltaT,
+		HouseSystem: hsys,
+		Sidereal:    opts.Sidereal,
+		Ayanamsa:    opts.Ayanamsa,
+	}
+
+	houseFlags := &swe.HousesExFlags{}
+	houseFlags.SetDeltaT(jdET.DeltaT)
+	if opts.Sidereal {
+		houseFlags.Flags = swe.FlagSidereal
+		houseFlags.SidMode = &swe.SidMode{Mode: opts.Ayanamsa}
+
+		aya, err := _swe.GetAyanamsaEx(jdET.Value(), &swe.AyanamsaExFlags{
+			Flags:   swe.FlagEphSwiss,
+			SidMode: &swe.SidMode{Mode: opts.Ayanamsa},
+			DeltaT:  &jdET.DeltaT,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Natal Ayanamsa: %w", err)
+		}
+		natal.AyanamsaValue = aya
+	}
+
+	cusps, ascmc, err := _swe.HousesEx(float64(jdUT), houseFlags, opts.Latitude, opts.Longitude, hsys)
+	if err != nil && hsys != swe.Porphyrius {
+		natal.HouseSystemError = fmt.Sprintf("%s: %s", string(hsys), err)
+		hsys = swe.Porphyrius
+		natal.HouseSystem = hsys
+		cusps, ascmc, err = _swe.HousesEx(float64(jdUT), houseFlags, opts.Latitude, opts.Longitude, hsys)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Natal HousesEx: %w", err)
+	}
+	natal.HouseSystemName, _ = _swe.HouseName(hsys)
+	copy(natal.Cusps[:], cusps[1:13])
+	natal.Angles = &Angles{
+		Asc:    ascmc[swe.Asc],
+		MC:     ascmc[swe.MC],
+		ARMC:   ascmc[swe.ARMC],
+		Vertex: ascmc[swe.Vertex],
+	}
+
+	calcFlags := &swe.CalcFlags{Flags: swe.FlagEphSwiss | swe.FlagSpeed, DeltaT: &jdET.DeltaT}
+
+	// 真黄赤交角, HousePos需要
+	nut, _, err := _swe.Calc(jdET.Value(), swe.EclNut, calcFlags)
+	if err != nil {
+		return nil, fmt.Errorf("Natal EclNut: %w", err)
+	}
+	eps := nut[0]
+
+	for _, planet := range bodies {
+		res, _, err := _swe.Calc(jdET.Value(), planet, calcFlags)
+		if err != nil {
+			natal.Unavailable = append(natal.Unavailable, &UnavailableBody{Planet: planet, Error: err.Error()})
+			continue
+		}
+
+		// 宫位无法计算时保留其它数据
+		var houseError string
+		house, err := _swe.HousePos(natal.Angles.ARMC, opts.Latitude, eps, hsys, res[0], res[1])
+		if err != nil {
+			house, houseError = 0, err.Error()
+		}
+
+		// 恒星黄道 = 回归黄道 - 岁差(含章动)
+		longitude := degreesMod360(res[0] - natal.AyanamsaValue)
+		sign, signDegree := SignOf(longitude)
+		name, _ := _swe.PlanetName(planet)
+
+		natal.Bodies = append(natal.Bodies, &BodyPosition{
+			Planet:     planet,
+			Name:       name,
+			Longitude:  longitude,
+			Latitude:   res[1],
+			Distance:   res[2],
+			Speed:      res[3],
+			Retrograde: res[3] < 0,
+			Sign:       sign,
+			SignName:   SignStrings[sign],
+			SignDegree: signDegree,
+			House:      house,
+			HouseError: houseError,
+		})
+	}
+
+	return natal, nil
+}
@@ -0,0 +1,129 @@
+package chart
+
+import (
+	"fmt"
+	"go-swe/src/astro"
+	"go-swe/src/swe"
+	"sort"
+	"time"
+)
+
+// TransitOptions 行运的计算参数
+type TransitOptions struct {
+	// 时间范围 [Start, End), 按照 Chart.Astronomy 的改历转为儒略日
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// 行运的天体, 为空时使用 DefaultBodies
+	Bodies []swe.Planet `json:"bodies"`
+	// 需要检测的相位, 为空时使用 MajorAspects, 容许度无效
+	Aspects []*AspectDefinition `json:"aspects"`
+	// 是否使用恒星黄道, 需与本命盘一致
+	Sidereal bool         `json:"sidereal"`
+	Ayanamsa swe.Ayanamsa `json:"ayanamsa"`
+	// 采样的步长 单位是 天, 默认为1天
+	// 需保证每一步的黄经变化小于180°, 且步长内最多只有一次留
+	Step float64 `json:"step"`
+}
+
+// Transit 行运天体与本命点形成精确相位的时刻
+type Transit struct {
+	JdUT       astro.JulianDay `json:"jd_ut"`
+	Transiting swe.Planet      `json:"transiting"`
+	Natal      swe.Planet      `json:"natal"`
+	Type       AspectType      `json:"type"`
+	Name       string          `json:"name"`
+	Angle      float64         `json:"angle"`
+	// 行运天体此时的黄经
+	Longitude float64 `json:"longitude"`
+	// 行运天体此时是否逆行
+	Retrograde bool `json:"retrograde"`
+}
+
+type transitTarget struct {
+	natal  *BodyPosition
+	aspect *AspectDefinition
+}
+
+// eclipticLongitudeFunc 天体黄经的 astro.AngleFunc, 可以是恒星黄道
+func (chart *Chart) eclipticLongitudeFunc(planet swe.Planet, sidereal bool, ayanamsa swe.Ayanamsa) astro.AngleFunc {
+	return func(jdUT astro.JulianDay) (float64, float64, error) {
+		jdET := chart.Astronomy.NewEphemerisTime(jdUT)
+		flags := &swe.CalcFlags{Flags: swe.FlagEphSwiss | swe.FlagSpeed | swe.FlagRadians, DeltaT: &jdET.DeltaT}
+		if sidereal {
+			flags.Flags |= swe.FlagSidereal
+			flags.SidMode = &swe.SidMode{Mode: ayanamsa}
+		}
+		res, _, err := chart.Astronomy.Swe.Calc(jdET.Value(), planet, flags)
+		if err != nil {
+			return 0, 0, err
+		}
+		return res[0], res[3], nil
+	}
+}
+
+// Transits 行运: 时间范围内, 行运天体与本命点形成精确相位的所有时刻, 按时间排序
+// 逆行时同一相位会出现3次, 均会返回
+//	natal 本命点, 比如 Natal.Bodies, 只使用其 Planet 和 Longitude
+//	opts 计算参数
+func (chart *Chart) Transits(natal []*BodyPosition, opts *TransitOptions) ([]*Transit, error) {
+	bodies := opts.Bodies
+	if len(bodies) == 0 {
+		bodies = DefaultBodies
+	}
+	aspects := opts.Aspects
+	if len(aspects) == 0 {
+		aspects = MajorAspects
+	}
+	step := astro.IfThenElse(opts.Step > 0, opts.Step, 1.).(float64)
+
+	// 每个本命点的每个相位, 在两侧各有一个目标黄经(合、冲只有一个)
+	var targets []transitTarget
+	var longitudes []float64
+	for _, point := range natal {
+		for _, aspect := range aspects {
+			for _, angle := range []float64{aspect.Angle, -aspect.Angle} {
+				longitudes = append(longitudes, astro.ToRadians(point.Longitude+angle))
+				targets = append(targets, transitTarget{natal: point, aspect: aspect})
+				if aspect.Angle == 0 || aspect.Angle == 180 {
+					break
+				}
+			}
+		}
+	}
+
+	start := chart.Astronomy.TimeToJulianDay(opts.Start)
+	end := chart.Astronomy.TimeToJulianDay(opts.End)
+
+	var transits []*Transit
+	for _, planet := range bodies {
+		angle := chart.eclipticLongitudeFunc(planet, opts.Sidereal, opts.Ayanamsa)
+
+		crossings, err := astro.FindAngleCrossings(start, end, step, longitudes, angle)
+		if err != nil {
+			return nil, fmt.Errorf("Transits %s: %w", planet, err)
+		}
+
+		for _, crossing := range crossings {
+			target := targets[crossing.Index]
+			longitude, speed, err := angle(crossing.JdUT)
+			if err != nil {
+				return nil, fmt.Errorf("Transits %s: %w", planet, err)
+			}
+			transits = append(transits, &Transit{
+				JdUT:       crossing.JdUT,
+				Transiting: planet,
+				Natal:      target.natal.Planet,
+				Type:       target.aspect.Type,
+				Name:       target.aspect.Name,
+				Angle:      target.aspect.Angle,
+				Longitude:  degreesMod360(astro.ToDegrees(longitude)),
+				Retrograde: speed < 0,
+			})
+		}
+	}
+
+	sort.SliceStable(transits, func(i, j int) bool {
+		return transits[i].JdUT < transits[j].JdUT
+	})
+	return transits, nil
+}
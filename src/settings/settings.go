@@ -7,6 +7,14 @@ type Settings struct {
 	Host  string `yaml:"host"`
 	Cert  string `yaml:"cert"`
 	Key   string `yaml:"key"`
+	// IERS Leap_Second.dat 格式的闰秒表, 为空时使用内置的闰秒表
+	LeapSecondFile string `yaml:"leap_second_file"`
+	// ΔT观测值表, 格式同swe的 swe_deltat.txt, 注册为名称为 observed 的ΔT模型
+	DeltaTFile string `yaml:"delta_t_file"`
+	// 默认的ΔT模型, 见 astro.GetDeltaTModel, 为空时为 swe
+	DeltaTModel string `yaml:"delta_t_model"`
+	// 默认的改历(儒略历改为格里高利历), 见 astro.GetCalendarReform, 为空时为 gregorian(1582-10-15)
+	CalendarReform string `yaml:"calendar_reform"`
 }
 
 func LoadSettings(filename string) (*Settings, error) {
@@ -15,6 +23,11 @@ func LoadSettings(filename string) (*Settings, error) {
 		Host:  "0.0.0.0:80",
 		Cert:  "",
 		Key:   "",
+
+		LeapSecondFile: "",
+		DeltaTFile:     "",
+		DeltaTModel:    "",
+		CalendarReform: "",
 	}
 
 	if err := conf.LoadSettings(settings, filename); err != nil {
@@ -214,6 +214,153 @@ const (
 	NodbitFoPoint  NodApsMethod = 256
 )
 
+// DeltaTModel is the type of SEMOD_DELTAT constants.
+type DeltaTModel int32
+
+// Delta T models defined in swephexp.h.
+const (
+	DeltaTDefault                   DeltaTModel = 0
+	DeltaTStephensonMorrison1984    DeltaTModel = 1
+	DeltaTStephenson1997            DeltaTModel = 2
+	DeltaTStephensonMorrison2004    DeltaTModel = 3
+	DeltaTEspenakMeeus2006          DeltaTModel = 4
+	DeltaTStephensonMorrisonHoh2016 DeltaTModel = 5
+)
+
+// Tidal acceleration values defined in swephexp.h.
+const (
+	TidalDE431          = -25.80
+	TidalStephenson2016 = -25.85
+	TidalDefault        = TidalDE431
+	TidalAutomatic      = 999999
+)
+
+// EclipseType is the type of eclipse flag constants.
+type EclipseType int32
+
+// Eclipse type and visibility flags defined in swephexp.h.
+const (
+	EclCentral          EclipseType = 1
+	EclNonCentral       EclipseType = 2
+	EclTotal            EclipseType = 4
+	EclAnnular          EclipseType = 8
+	EclPartial          EclipseType = 16
+	EclAnnularTotal     EclipseType = 32
+	EclHybrid           EclipseType = EclAnnularTotal
+	EclPenumbral        EclipseType = 64
+	EclAllTypesSolar    EclipseType = EclCentral | EclNonCentral | EclTotal | EclAnnular | EclPartial | EclAnnularTotal
+	EclAllTypesLunar    EclipseType = EclTotal | EclPartial | EclPenumbral
+	EclVisible          EclipseType = 128
+	EclMaxVisible       EclipseType = 256
+	Ecl1stVisible       EclipseType = 512  // begin of partial eclipse
+	Ecl2ndVisible       EclipseType = 1024 // begin of total eclipse
+	Ecl3rdVisible       EclipseType = 2048 // end of total eclipse
+	Ecl4thVisible       EclipseType = 4096 // end of partial eclipse
+	EclPenumbBegVisible EclipseType = 8192
+	EclPenumbEndVisible EclipseType = 16384
+)
+
+// Has reports whether all bits of flag are set in t.
+func (t EclipseType) Has(flag EclipseType) bool {
+	return t&flag == flag
+}
+
+// Indexes of the times returned by SolEclipseWhenGlob defined in swephexp.h.
+const (
+	SolEclGlobMax               = 0
+	SolEclGlobLocalNoon         = 1 // eclipse at local apparent noon
+	SolEclGlobBegin             = 2
+	SolEclGlobEnd               = 3
+	SolEclGlobTotalBegin        = 4
+	SolEclGlobTotalEnd          = 5
+	SolEclGlobCenterBegin       = 6 // begin of the center line
+	SolEclGlobCenterEnd         = 7 // end of the center line
+	SolEclGlobAnnularTotalBegin = 8 // annular-total eclipse becomes total
+	SolEclGlobAnnularTotalEnd   = 9 // annular-total eclipse becomes annular again
+)
+
+// Indexes of the times returned by SolEclipseWhenLoc defined in swephexp.h.
+const (
+	SolEclLocMax           = 0
+	SolEclLocFirstContact  = 1
+	SolEclLocSecondContact = 2
+	SolEclLocThirdContact  = 3
+	SolEclLocFourthContact = 4
+	SolEclLocSunrise       = 5
+	SolEclLocSunset        = 6
+)
+
+// Indexes of the solar eclipse attributes returned by SolEclipseHow,
+// SolEclipseWhere and SolEclipseWhenLoc defined in swephexp.h.
+const (
+	SolEclAttrDiameterFraction   = 0 // fraction of the solar diameter covered by the moon
+	SolEclAttrDiameterRatio      = 1 // ratio of the lunar diameter to the solar one
+	SolEclAttrObscuration        = 2 // fraction of the solar disc covered by the moon
+	SolEclAttrCoreShadowDiameter = 3 // in km
+	SolEclAttrAzimuth            = 4 // azimuth of the sun
+	SolEclAttrTrueAltitude       = 5 // true altitude of the sun above horizon
+	SolEclAttrApparentAltitude   = 6 // apparent altitude of the sun above horizon
+	SolEclAttrElongation         = 7 // angular distance of the moon from the sun
+	SolEclAttrMagnitude          = 8 // eclipse magnitude according to NASA
+	SolEclAttrSarosSeries        = 9
+	SolEclAttrSarosMember        = 10
+)
+
+// Indexes of the geographic positions returned by SolEclipseWhere defined in
+// swephexp.h.
+const (
+	SolEclGeoLongitude = 0 // longitude of the maximum eclipse
+	SolEclGeoLatitude  = 1 // latitude of the maximum eclipse
+)
+
+// Indexes of the times returned by LunEclipseWhen and LunEclipseWhenLoc
+// defined in swephexp.h.
+const (
+	LunEclMax            = 0
+	LunEclPartialBegin   = 2
+	LunEclPartialEnd     = 3
+	LunEclTotalBegin     = 4
+	LunEclTotalEnd       = 5
+	LunEclPenumbralBegin = 6
+	LunEclPenumbralEnd   = 7
+	LunEclMoonrise       = 8 // LunEclipseWhenLoc only
+	LunEclMoonset        = 9 // LunEclipseWhenLoc only
+)
+
+// Indexes of the lunar eclipse attributes returned by LunEclipseHow and
+// LunEclipseWhenLoc defined in swephexp.h.
+const (
+	LunEclAttrUmbralMagnitude    = 0
+	LunEclAttrPenumbralMagnitude = 1
+	LunEclAttrAzimuth            = 4 // azimuth of the moon
+	LunEclAttrTrueAltitude       = 5 // true altitude of the moon above horizon
+	LunEclAttrApparentAltitude   = 6 // apparent altitude of the moon above horizon
+	LunEclAttrOppositionDistance = 7 // distance of the moon from the opposition in degrees
+	LunEclAttrSarosSeries        = 9
+	LunEclAttrSarosMember        = 10
+)
+
+// RiseTransMode is the type of rise, set and transit calculation constants.
+type RiseTransMode int32
+
+// Rise, set and transit calculation flags defined in swephexp.h.
+const (
+	CalcRise           RiseTransMode = 1
+	CalcSet            RiseTransMode = 2
+	CalcMTransit       RiseTransMode = 4 // upper meridian transit
+	CalcITransit       RiseTransMode = 8 // lower meridian transit
+	BitDiscCenter      RiseTransMode = 256
+	BitDiscBottom      RiseTransMode = 8192
+	BitGeoCtrNoEclLat  RiseTransMode = 128
+	BitNoRefraction    RiseTransMode = 512
+	BitCivilTwilight   RiseTransMode = 1024
+	BitNauticTwilight  RiseTransMode = 2048
+	BitAstroTwilight   RiseTransMode = 4096
+	BitFixedDiscSize   RiseTransMode = 16384
+	BitForceSlowMethod RiseTransMode = 32768
+	BitHinduRising     RiseTransMode = BitDiscCenter | BitNoRefraction | BitGeoCtrNoEclLat
+)
+
 // File name of JPL data files defined in swephexp.h.
 const (
 	FnameDE200 = "de200.eph"
@@ -355,6 +502,41 @@ func NewHSys(c byte) (hsys HSys, ok bool) {
 	}
 }
 
+// EclipseFlags represents the library state of swe_sol_eclipse_when_glob,
+// swe_sol_eclipse_when_loc, swe_sol_eclipse_where, swe_sol_eclipse_how and
+// their lunar counterparts.
+type EclipseFlags struct {
+	Flags  int32    // ephemeris flag
+	DeltaT *float64 // Argument to swe_set_delta_t_userdef, nil resets it.
+}
+
+// SetDeltaT sets f as delta T in flags object fl.
+// Set fl.DeltaT to nil to reset the value within the Swiss Ephemeris.
+func (ef *EclipseFlags) SetDeltaT(f float64) {
+	ef.DeltaT = &f
+}
+
+// RiseTransFlags represents the library state of swe_rise_trans and
+// swe_rise_trans_true_hor.
+type RiseTransFlags struct {
+	Flags  int32    // ephemeris flag
+	DeltaT *float64 // Argument to swe_set_delta_t_userdef, nil resets it.
+}
+
+// SetDeltaT sets f as delta T in flags object fl.
+// Set fl.DeltaT to nil to reset the value within the Swiss Ephemeris.
+func (rf *RiseTransFlags) SetDeltaT(f float64) {
+	rf.DeltaT = &f
+}
+
+// DeltaTFlags represents the library state of swe_deltat_ex when used with
+// a specific delta T model.
+type DeltaTFlags struct {
+	Ephemeris Ephemeris   // The ephemeris used to determine the tidal acceleration
+	Model     DeltaTModel // Argument to swe_set_astro_models, 0 is the default model
+	TidAcc    *float64    // Argument to swe_set_tid_acc, nil is automatic.
+}
+
 // TimeEquFlags represents the library state of swe_time_equ, swe_lmt_to_lat
 // and swe_lat_to_lmt.
 type TimeEquFlags struct {
@@ -20,3 +20,7 @@ func (e *SweError) Error() string {
 func (e *SweError) Unwrap() error {
 	return e.err
 }
+
+// errEmptyStarName is returned by the fixed star functions for an empty name,
+// which the C library would dereference.
+var errEmptyStarName = NewSweError("fixed star name is empty")
@@ -337,7 +337,8 @@ func housePos(armc, geolat, eps float64, hsys HSys, pllng, pllat float64) (pos f
 	_lat := C.double(geolat)
 	_eps := C.double(eps)
 	_hsys := C.int(hsys)
-	xpin := [2]C.double{C.double(pllat), C.double(pllng)}
+	// xpin holds the ecliptic longitude and latitude, in that order.
+	xpin := [2]C.double{C.double(pllng), C.double(pllat)}
 
 	err = withError(func(err *C.char) bool {
 		pos = float64(C.swe_house_pos(_armc, _lat, _eps, _hsys, &xpin[0], err))
@@ -368,6 +369,16 @@ func setDeltaTUserDef(v float64) {
 	C.swe_set_delta_t_userdef(C.double(v))
 }
 
+func setAstroModels(samod string, eph int32) {
+	_samod := C.CString(samod)
+	C.swe_set_astro_models(_samod, C.int32(eph))
+	C.free(unsafe.Pointer(_samod))
+}
+
+func setTidAcc(acc float64) {
+	C.swe_set_tid_acc(C.double(acc))
+}
+
 func timeEqu(jd float64) (E float64, err error) {
 	var _E C.double
 
@@ -428,3 +439,262 @@ func sidTime0(ut, eps, nut float64) float64 {
 func sidTime(ut float64) float64 {
 	return float64(C.swe_sidtime(C.double(ut)))
 }
+
+func boolToInt32(b bool) C.int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// geoPos returns the geographic position array expected by the eclipse
+// functions of the C library: longitude, latitude (both in degrees) and
+// altitude above sea level (in meters).
+func geoPos(geo *GeoLoc) [3]C.double {
+	if geo == nil {
+		return [3]C.double{}
+	}
+	return [3]C.double{C.double(geo.Long), C.double(geo.Lat), C.double(geo.Alt)}
+}
+
+type _eclipseWhenGlobFunc func(jd C.double, fl, ifltype C.int32, tret *C.double, backward C.int32, err *C.char) C.int32
+
+func _eclipseWhenGlob(ut float64, fl int32, ecl EclipseType, backward bool, fn _eclipseWhenGlobFunc) (_ []float64, typ EclipseType, err error) {
+	// Both the float64 and C.double types are defined as an IEEE-754 64-bit
+	// floating-point number, see _calc.
+	var tret [10]float64
+	_tret := (*C.double)(unsafe.Pointer(&tret[0]))
+
+	err = withError(func(err *C.char) bool {
+		typ = EclipseType(fn(C.double(ut), C.int32(fl), C.int32(ecl), _tret, boolToInt32(backward), err))
+		return typ == C.ERR
+	})
+
+	if err != nil {
+		typ = 0
+	}
+
+	return tret[:], typ, err
+}
+
+func solEclipseWhenGlob(ut float64, fl int32, ecl EclipseType, backward bool) ([]float64, EclipseType, error) {
+	return _eclipseWhenGlob(ut, fl, ecl, backward, func(jd C.double, fl, ifltype C.int32, tret *C.double, backward C.int32, err *C.char) C.int32 {
+		return C.swe_sol_eclipse_when_glob(jd, fl, ifltype, tret, backward, err)
+	})
+}
+
+type _eclipseWhenLocFunc func(jd C.double, fl C.int32, geopos, tret, attr *C.double, backward C.int32, err *C.char) C.int32
+
+func _eclipseWhenLoc(ut float64, fl int32, geo *GeoLoc, backward bool, fn _eclipseWhenLocFunc) (_, _ []float64, typ EclipseType, err error) {
+	_geopos := geoPos(geo)
+
+	var tret [10]float64
+	var attr [20]float64
+	_tret := (*C.double)(unsafe.Pointer(&tret[0]))
+	_attr := (*C.double)(unsafe.Pointer(&attr[0]))
+
+	err = withError(func(err *C.char) bool {
+		typ = EclipseType(fn(C.double(ut), C.int32(fl), &_geopos[0], _tret, _attr, boolToInt32(backward), err))
+		return typ == C.ERR
+	})
+
+	if err != nil {
+		typ = 0
+	}
+
+	return tret[:], attr[:], typ, err
+}
+
+func solEclipseWhenLoc(ut float64, fl int32, geo *GeoLoc, backward bool) ([]float64, []float64, EclipseType, error) {
+	return _eclipseWhenLoc(ut, fl, geo, backward, func(jd C.double, fl C.int32, geopos, tret, attr *C.double, backward C.int32, err *C.char) C.int32 {
+		return C.swe_sol_eclipse_when_loc(jd, fl, geopos, tret, attr, backward, err)
+	})
+}
+
+func solEclipseWhere(ut float64, fl int32) (_, _ []float64, typ EclipseType, err error) {
+	var geopos [10]float64
+	var attr [20]float64
+	_geopos := (*C.double)(unsafe.Pointer(&geopos[0]))
+	_attr := (*C.double)(unsafe.Pointer(&attr[0]))
+
+	err = withError(func(err *C.char) bool {
+		typ = EclipseType(C.swe_sol_eclipse_where(C.double(ut), C.int32(fl), _geopos, _attr, err))
+		return typ == C.ERR
+	})
+
+	if err != nil {
+		typ = 0
+	}
+
+	return geopos[:], attr[:], typ, err
+}
+
+type _eclipseHowFunc func(jd C.double, fl C.int32, geopos, attr *C.double, err *C.char) C.int32
+
+func _eclipseHow(ut float64, fl int32, geo *GeoLoc, fn _eclipseHowFunc) (_ []float64, typ EclipseType, err error) {
+	_geopos := geoPos(geo)
+
+	var attr [20]float64
+	_attr := (*C.double)(unsafe.Pointer(&attr[0]))
+
+	err = withError(func(err *C.char) bool {
+		typ = EclipseType(fn(C.double(ut), C.int32(fl), &_geopos[0], _attr, err))
+		return typ == C.ERR
+	})
+
+	if err != nil {
+		typ = 0
+	}
+
+	return attr[:], typ, err
+}
+
+func solEclipseHow(ut float64, fl int32, geo *GeoLoc) ([]float64, EclipseType, error) {
+	return _eclipseHow(ut, fl, geo, func(jd C.double, fl C.int32, geopos, attr *C.double, err *C.char) C.int32 {
+		return C.swe_sol_eclipse_how(jd, fl, geopos, attr, err)
+	})
+}
+
+func lunEclipseWhen(ut float64, fl int32, ecl EclipseType, backward bool) ([]float64, EclipseType, error) {
+	return _eclipseWhenGlob(ut, fl, ecl, backward, func(jd C.double, fl, ifltype C.int32, tret *C.double, backward C.int32, err *C.char) C.int32 {
+		return C.swe_lun_eclipse_when(jd, fl, ifltype, tret, backward, err)
+	})
+}
+
+func lunEclipseWhenLoc(ut float64, fl int32, geo *GeoLoc, backward bool) ([]float64, []float64, EclipseType, error) {
+	return _eclipseWhenLoc(ut, fl, geo, backward, func(jd C.double, fl C.int32, geopos, tret, attr *C.double, backward C.int32, err *C.char) C.int32 {
+		return C.swe_lun_eclipse_when_loc(jd, fl, geopos, tret, attr, backward, err)
+	})
+}
+
+func lunEclipseHow(ut float64, fl int32, geo *GeoLoc) ([]float64, EclipseType, error) {
+	return _eclipseHow(ut, fl, geo, func(jd C.double, fl C.int32, geopos, attr *C.double, err *C.char) C.int32 {
+		return C.swe_lun_eclipse_how(jd, fl, geopos, attr, err)
+	})
+}
+
+// starName copies the fixed star name into a buffer that is large enough for
+// the C library to write back the full name of the star. An empty name
+// returns nil, which only swe_rise_trans and swe_rise_trans_true_hor accept.
+func starName(star string, buf *[2 * C.SE_MAX_STNAME]C.char) (*C.char, error) {
+	if star == "" {
+		return nil, nil
+	}
+	if len(star) >= len(buf) {
+		return nil, NewSweError("fixed star name is too long: " + star)
+	}
+
+	for i := 0; i < len(star); i++ {
+		buf[i] = C.char(star[i])
+	}
+
+	return &buf[0], nil
+}
+
+type _riseTransFunc func(jd C.double, ipl C.int32, starname *C.char, fl, rsmi C.int32, geopos, tret *C.double, err *C.char) C.int32
+
+func _riseTrans(ut float64, pl Planet, star string, fl int32, rsmi RiseTransMode, geo *GeoLoc, fn _riseTransFunc) (tret float64, circumpolar bool, err error) {
+	_geopos := geoPos(geo)
+
+	var _star [2 * C.SE_MAX_STNAME]C.char
+	_tret := (*C.double)(unsafe.Pointer(&tret))
+	starname, err := starName(star, &_star)
+	if err != nil {
+		return 0, false, err
+	}
+
+	err = withError(func(err *C.char) bool {
+		rc := fn(C.double(ut), C.int32(pl), starname, C.int32(fl), C.int32(rsmi), &_geopos[0], _tret, err)
+		// -2 means the body is circumpolar, it does not rise or set
+		circumpolar = rc == -2
+		return rc == C.ERR
+	})
+
+	return tret, circumpolar, err
+}
+
+func riseTrans(ut float64, pl Planet, star string, fl int32, rsmi RiseTransMode, geo *GeoLoc, atpress, attemp float64) (float64, bool, error) {
+	return _riseTrans(ut, pl, star, fl, rsmi, geo, func(jd C.double, ipl C.int32, starname *C.char, fl, rsmi C.int32, geopos, tret *C.double, err *C.char) C.int32 {
+		return C.swe_rise_trans(jd, ipl, starname, fl, rsmi, geopos, C.double(atpress), C.double(attemp), tret, err)
+	})
+}
+
+func riseTransTrueHor(ut float64, pl Planet, star string, fl int32, rsmi RiseTransMode, geo *GeoLoc, atpress, attemp, horhgt float64) (float64, bool, error) {
+	return _riseTrans(ut, pl, star, fl, rsmi, geo, func(jd C.double, ipl C.int32, starname *C.char, fl, rsmi C.int32, geopos, tret *C.double, err *C.char) C.int32 {
+		return C.swe_rise_trans_true_hor(jd, ipl, starname, fl, rsmi, geopos, C.double(atpress), C.double(attemp), C.double(horhgt), tret, err)
+	})
+}
+
+type _fixStarFunc func(star *C.char, jd C.double, fl C.int32, xx *C.double, err *C.char) C.int32
+
+func _fixStar(star string, jd float64, fl int32, fn _fixStarFunc) (name string, _ []float64, cfl int, err error) {
+	var _star [2 * C.SE_MAX_STNAME]C.char
+	starname, err := starName(star, &_star)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	// See _calc for the cast between the float64 and C.double arrays.
+	var xx [6]float64
+	_xx := (*C.double)(unsafe.Pointer(&xx[0]))
+
+	err = withError(func(err *C.char) bool {
+		cfl = int(fn(starname, C.double(jd), C.int32(fl), _xx, err))
+		return cfl == C.ERR
+	})
+
+	return C.GoString(&_star[0]), xx[:], cfl, err
+}
+
+func fixStar(star string, et float64, fl int32) (string, []float64, int, error) {
+	return _fixStar(star, et, fl, func(star *C.char, jd C.double, fl C.int32, xx *C.double, err *C.char) C.int32 {
+		return C.swe_fixstar(star, jd, fl, xx, err)
+	})
+}
+
+func fixStarUT(star string, ut float64, fl int32) (string, []float64, int, error) {
+	return _fixStar(star, ut, fl, func(star *C.char, jd C.double, fl C.int32, xx *C.double, err *C.char) C.int32 {
+		return C.swe_fixstar_ut(star, jd, fl, xx, err)
+	})
+}
+
+func fixStarMag(star string) (name string, mag float64, err error) {
+	var _star [2 * C.SE_MAX_STNAME]C.char
+	_mag := (*C.double)(unsafe.Pointer(&mag))
+	starname, err := starName(star, &_star)
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = withError(func(err *C.char) bool {
+		return C.swe_fixstar_mag(starname, _mag, err) == C.ERR
+	})
+
+	return C.GoString(&_star[0]), mag, err
+}
+
+type _phenoFunc func(jd C.double, ipl, fl C.int32, attr *C.double, err *C.char) C.int32
+
+func _pheno(jd float64, pl Planet, fl int32, fn _phenoFunc) (_ []float64, err error) {
+	// See _calc for the cast between the float64 and C.double arrays.
+	var attr [20]float64
+	_attr := (*C.double)(unsafe.Pointer(&attr[0]))
+
+	err = withError(func(err *C.char) bool {
+		return fn(C.double(jd), C.int32(pl), C.int32(fl), _attr, err) == C.ERR
+	})
+
+	return attr[:], err
+}
+
+func pheno(et float64, pl Planet, fl int32) ([]float64, error) {
+	return _pheno(et, pl, fl, func(jd C.double, ipl, fl C.int32, attr *C.double, err *C.char) C.int32 {
+		return C.swe_pheno(jd, ipl, fl, attr, err)
+	})
+}
+
+func phenoUT(ut float64, pl Planet, fl int32) ([]float64, error) {
+	return _pheno(ut, pl, fl, func(jd C.double, ipl, fl C.int32, attr *C.double, err *C.char) C.int32 {
+		return C.swe_pheno_ut(jd, ipl, fl, attr, err)
+	})
+}
@@ -1,6 +1,7 @@
 package swe
 
 import (
+	"strconv"
 	"sync"
 )
 
@@ -85,6 +86,10 @@ type BaseInterface interface {
 	DeltaTEx(jd float64, eph Ephemeris) (float64, error)
 	// 粗略的计算ET - UT
 	DeltaT(jd float64) float64
+	// DeltaTModel returns the ΔT for the Julian Date jd, calculated with
+	// the delta T model and the tidal acceleration passed in fl.
+	// The library state is restored to the default models afterwards.
+	DeltaTModel(jd float64, fl *DeltaTFlags) (float64, error)
 
 	// TimeEqu returns the difference between local apparent and local mean time
 	// in days for the given Julian Date (in Universal Time).
@@ -104,6 +109,91 @@ type BaseInterface interface {
 	// medidian, measured in hours.
 	SidTime(ut float64, fl *SidTimeFlags) (float64, error)
 	//SidTime(ut float64) float64
+
+	// SolEclipseWhenGlob finds the next solar eclipse of type ecl (0 for any
+	// type) globally after Julian Date (in Universal Time) ut, or the previous
+	// one if backward is true. The returned tret holds the time of maximum
+	// eclipse, the time of the eclipse at local apparent noon, the begin and end
+	// of the eclipse, of the totality and of the center line, and the times of
+	// the annular-total transitions, indexed by the SolEclGlob constants.
+	SolEclipseWhenGlob(ut float64, fl *EclipseFlags, ecl EclipseType, backward bool) (tret []float64, typ EclipseType, err error)
+	// SolEclipseWhenLoc finds the next solar eclipse visible from the
+	// geographic location geo after Julian Date (in Universal Time) ut, or the
+	// previous one if backward is true. The returned tret holds the time of
+	// maximum eclipse, the first to fourth contacts, sunrise and sunset,
+	// indexed by the SolEclLoc constants. The returned attr holds the
+	// attributes as returned by SolEclipseHow.
+	SolEclipseWhenLoc(ut float64, fl *EclipseFlags, geo *GeoLoc, backward bool) (tret, attr []float64, typ EclipseType, err error)
+	// SolEclipseWhere returns the geographic location of the maximum of the
+	// solar eclipse at Julian Date (in Universal Time) ut and the attributes of
+	// the eclipse at that location. Geopos holds the longitude and latitude of
+	// the maximum eclipse followed by the northern and southern limits of the
+	// umbra and penumbra, all in degrees, see SolEclGeoLongitude and
+	// SolEclGeoLatitude.
+	SolEclipseWhere(ut float64, fl *EclipseFlags) (geopos, attr []float64, typ EclipseType, err error)
+	// SolEclipseHow returns the attributes of the solar eclipse at Julian Date
+	// (in Universal Time) ut for the geographic location geo: magnitude, ratio
+	// of the lunar and solar diameters, obscuration, diameter of the core
+	// shadow, azimuth and altitude of the sun, elongation of the moon,
+	// magnitude according to NASA, saros series and saros member number,
+	// indexed by the SolEclAttr constants.
+	// A returned type of 0 means there is no eclipse at geo.
+	SolEclipseHow(ut float64, fl *EclipseFlags, geo *GeoLoc) (attr []float64, typ EclipseType, err error)
+
+	// LunEclipseWhen finds the next lunar eclipse of type ecl (0 for any type)
+	// after Julian Date (in Universal Time) ut, or the previous one if backward
+	// is true. The returned tret holds the time of maximum eclipse, the begin
+	// and end of the partial phase, of the totality and of the penumbral
+	// phase, indexed by the LunEcl constants.
+	LunEclipseWhen(ut float64, fl *EclipseFlags, ecl EclipseType, backward bool) (tret []float64, typ EclipseType, err error)
+	// LunEclipseWhenLoc finds the next lunar eclipse visible from the
+	// geographic location geo after Julian Date (in Universal Time) ut, or the
+	// previous one if backward is true. The returned tret holds the same times
+	// as LunEclipseWhen followed by moonrise and moonset. The returned attr
+	// holds the attributes as returned by LunEclipseHow.
+	LunEclipseWhenLoc(ut float64, fl *EclipseFlags, geo *GeoLoc, backward bool) (tret, attr []float64, typ EclipseType, err error)
+	// LunEclipseHow returns the attributes of the lunar eclipse at Julian Date
+	// (in Universal Time) ut: umbral magnitude, penumbral magnitude, azimuth
+	// and altitude of the moon for the geographic location geo, distance of
+	// the moon from the opposition, saros series and saros member number,
+	// indexed by the LunEclAttr constants.
+	// A returned type of 0 means there is no eclipse.
+	LunEclipseHow(ut float64, fl *EclipseFlags, geo *GeoLoc) (attr []float64, typ EclipseType, err error)
+
+	// RiseTrans finds the next rising, setting or meridian transit, as selected
+	// by rsmi, of planet pl or fixed star star (when not empty) after Julian
+	// Date (in Universal Time) ut, for the geographic location geo. Atpress is
+	// the atmospheric pressure in hPa (0 estimates it from the altitude of
+	// geo) and attemp the atmospheric temperature in degrees Celsius.
+	// Circumpolar is true when the body does not rise or set at geo.
+	RiseTrans(ut float64, pl Planet, star string, fl *RiseTransFlags, rsmi RiseTransMode, geo *GeoLoc, atpress, attemp float64) (tret float64, circumpolar bool, err error)
+	// RiseTransTrueHor is like RiseTrans, but takes the height of the local
+	// horizon horhgt (in degrees) into account.
+	RiseTransTrueHor(ut float64, pl Planet, star string, fl *RiseTransFlags, rsmi RiseTransMode, geo *GeoLoc, atpress, attemp, horhgt float64) (tret float64, circumpolar bool, err error)
+
+	// FixStar computes the position of fixed star star in Ephemeris Time
+	// (TT) et. Star is either the traditional name, the nomenclature name
+	// prefixed by a comma (e.g. ",alTau") or the sequential number in the
+	// catalog sefstars.txt. The returned name is the full name of the star as
+	// "traditional name,nomenclature name". An empty or too long star
+	// returns an error.
+	FixStar(star string, et float64, fl *CalcFlags) (name string, xx []float64, cfl int, err error)
+	// FixStarUT computes the position of fixed star star in Universal Time ut.
+	// See FixStar.
+	FixStarUT(star string, ut float64, fl *CalcFlags) (name string, xx []float64, cfl int, err error)
+	// FixStarMag returns the visual magnitude of fixed star star.
+	// See FixStar.
+	FixStarMag(star string) (name string, mag float64, err error)
+
+	// Pheno computes the phenomena of planet pl in Ephemeris Time (TT) et.
+	// The returned attr holds the phase angle (earth-planet-sun), the phase
+	// (illuminated fraction of the disc), the elongation of the planet, the
+	// apparent diameter of the disc, all angles in degrees, the apparent
+	// magnitude and the horizontal parallax (Moon only).
+	Pheno(et float64, pl Planet, fl *CalcFlags) (attr []float64, err error)
+	// PhenoUT computes the phenomena of planet pl in Universal Time ut.
+	// See Pheno.
+	PhenoUT(ut float64, pl Planet, fl *CalcFlags) (attr []float64, err error)
 }
 
 // SweInterface extends the main library interface by exposing C library
@@ -129,6 +219,14 @@ type SweInterface interface {
 	// The ephemeris can be reopened by calling SetPath.
 	Close()
 
+	// EphePath returns the data path set by SetPath, DefaultPath if unset.
+	EphePath() string
+
+	// Reload closes and reopens the ephemeris with the current data path
+	// in a single locked call, so cached files like the fixed star catalog
+	// are read again.
+	Reload()
+
 	// used for locking and prevent other interface implementations
 	acquire()
 	release()
@@ -142,7 +240,7 @@ var sweInstance SweInterface
 func NewSwe() SweInterface {
 	once.Do(func() {
 		checkLibrary()
-		sweInstance = &swe{locker: new(sync.Mutex)}
+		sweInstance = &swe{locker: new(sync.Mutex), ephePath: DefaultPath}
 	})
 
 	return sweInstance
@@ -167,7 +265,8 @@ func OpenWithPath(ephePath string) SweInterface {
 // It protect stateful library functions with a mutex. When the swe is
 // exclusively locked, the mutex is temporary replaced by a no-op lock.
 type swe struct {
-	locker sync.Locker
+	locker   sync.Locker
+	ephePath string
 }
 
 func (s *swe) acquire() { s.locker.Lock() }
@@ -185,6 +284,7 @@ func (s *swe) Version() (string, error) {
 func (s *swe) SetPath(ephePath string) {
 	s.acquire()
 	setEphePath(ephePath)
+	s.ephePath = ephePath
 	s.release()
 }
 
@@ -194,6 +294,20 @@ func (s *swe) Close() {
 	s.release()
 }
 
+func (s *swe) EphePath() string {
+	s.acquire()
+	ephePath := s.ephePath
+	s.release()
+	return ephePath
+}
+
+func (s *swe) Reload() {
+	s.acquire()
+	closeEphemeris()
+	setEphePath(s.ephePath)
+	s.release()
+}
+
 const resetDeltaT = -1e-10
 
 func setDeltaT(dt *float64) {
@@ -391,7 +505,36 @@ func (s *swe) DeltaTEx(jd float64, eph Ephemeris) (float64, error) {
 }
 
 func (s *swe) DeltaT(jd float64) float64 {
-	return deltaT(jd)
+	s.acquire()
+	setDeltaT(nil)
+	dt := deltaT(jd)
+	s.release()
+	return dt
+}
+
+func (s *swe) DeltaTModel(jd float64, df *DeltaTFlags) (float64, error) {
+	var eph Ephemeris = FlagEphSwiss
+	var model DeltaTModel
+	var tidAcc float64 = TidalAutomatic
+	if df != nil {
+		if df.Ephemeris != 0 {
+			eph = df.Ephemeris
+		}
+		model = df.Model
+		if df.TidAcc != nil {
+			tidAcc = *df.TidAcc
+		}
+	}
+
+	s.acquire()
+	setDeltaT(nil)
+	setAstroModels(strconv.Itoa(int(model)), int32(eph))
+	setTidAcc(tidAcc)
+	dt, err := deltaTEx(jd, int32(eph))
+	setAstroModels("0", int32(eph))
+	setTidAcc(TidalAutomatic)
+	s.release()
+	return dt, err
 }
 
 func setTimeEquDeltaT(tf *TimeEquFlags) {
@@ -466,3 +609,143 @@ func (s *swe) SidTime(ut float64, stf *SidTimeFlags) (float64, error) {
 //func (s *swe) SidTime(ut float64) float64 {
 //	return sidTime(ut)
 //}
+
+func setEclipseFlagsState(ef *EclipseFlags) int32 {
+	if ef == nil {
+		setDeltaT(nil)
+		return 0
+	}
+
+	setDeltaT(ef.DeltaT)
+	return ef.Flags
+}
+
+func (s *swe) SolEclipseWhenGlob(ut float64, ef *EclipseFlags, ecl EclipseType, backward bool) ([]float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	tret, typ, err := solEclipseWhenGlob(ut, flags, ecl, backward)
+	s.release()
+	return tret, typ, err
+}
+
+func (s *swe) SolEclipseWhenLoc(ut float64, ef *EclipseFlags, geo *GeoLoc, backward bool) ([]float64, []float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	tret, attr, typ, err := solEclipseWhenLoc(ut, flags, geo, backward)
+	s.release()
+	return tret, attr, typ, err
+}
+
+func (s *swe) SolEclipseWhere(ut float64, ef *EclipseFlags) ([]float64, []float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	geopos, attr, typ, err := solEclipseWhere(ut, flags)
+	s.release()
+	return geopos, attr, typ, err
+}
+
+func (s *swe) SolEclipseHow(ut float64, ef *EclipseFlags, geo *GeoLoc) ([]float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	attr, typ, err := solEclipseHow(ut, flags, geo)
+	s.release()
+	return attr, typ, err
+}
+
+func (s *swe) LunEclipseWhen(ut float64, ef *EclipseFlags, ecl EclipseType, backward bool) ([]float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	tret, typ, err := lunEclipseWhen(ut, flags, ecl, backward)
+	s.release()
+	return tret, typ, err
+}
+
+func (s *swe) LunEclipseWhenLoc(ut float64, ef *EclipseFlags, geo *GeoLoc, backward bool) ([]float64, []float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	tret, attr, typ, err := lunEclipseWhenLoc(ut, flags, geo, backward)
+	s.release()
+	return tret, attr, typ, err
+}
+
+func (s *swe) LunEclipseHow(ut float64, ef *EclipseFlags, geo *GeoLoc) ([]float64, EclipseType, error) {
+	s.acquire()
+	flags := setEclipseFlagsState(ef)
+	attr, typ, err := lunEclipseHow(ut, flags, geo)
+	s.release()
+	return attr, typ, err
+}
+
+func setRiseTransFlagsState(rf *RiseTransFlags) int32 {
+	if rf == nil {
+		setDeltaT(nil)
+		return 0
+	}
+
+	setDeltaT(rf.DeltaT)
+	return rf.Flags
+}
+
+func (s *swe) RiseTrans(ut float64, pl Planet, star string, rf *RiseTransFlags, rsmi RiseTransMode, geo *GeoLoc, atpress, attemp float64) (float64, bool, error) {
+	s.acquire()
+	flags := setRiseTransFlagsState(rf)
+	tret, circumpolar, err := riseTrans(ut, pl, star, flags, rsmi, geo, atpress, attemp)
+	s.release()
+	return tret, circumpolar, err
+}
+
+func (s *swe) RiseTransTrueHor(ut float64, pl Planet, star string, rf *RiseTransFlags, rsmi RiseTransMode, geo *GeoLoc, atpress, attemp, horhgt float64) (float64, bool, error) {
+	s.acquire()
+	flags := setRiseTransFlagsState(rf)
+	tret, circumpolar, err := riseTransTrueHor(ut, pl, star, flags, rsmi, geo, atpress, attemp, horhgt)
+	s.release()
+	return tret, circumpolar, err
+}
+
+func (s *swe) FixStar(star string, et float64, cf *CalcFlags) (string, []float64, int, error) {
+	if star == "" {
+		return "", nil, 0, errEmptyStarName
+	}
+	s.acquire()
+	flags := setCalcFlagsState(cf)
+	name, xx, cfl, err := fixStar(star, et, flags)
+	s.release()
+	return name, xx, cfl, err
+}
+
+func (s *swe) FixStarUT(star string, ut float64, cf *CalcFlags) (string, []float64, int, error) {
+	if star == "" {
+		return "", nil, 0, errEmptyStarName
+	}
+	s.acquire()
+	flags := setCalcFlagsState(cf)
+	name, xx, cfl, err := fixStarUT(star, ut, flags)
+	s.release()
+	return name, xx, cfl, err
+}
+
+func (s *swe) FixStarMag(star string) (string, float64, error) {
+	if star == "" {
+		return "", 0, errEmptyStarName
+	}
+	s.acquire()
+	name, mag, err := fixStarMag(star)
+	s.release()
+	return name, mag, err
+}
+
+func (s *swe) Pheno(et float64, pl Planet, cf *CalcFlags) ([]float64, error) {
+	s.acquire()
+	flags := setCalcFlagsState(cf)
+	attr, err := pheno(et, pl, flags)
+	s.release()
+	return attr, err
+}
+
+func (s *swe) PhenoUT(ut float64, pl Planet, cf *CalcFlags) ([]float64, error) {
+	s.acquire()
+	flags := setCalcFlagsState(cf)
+	attr, err := phenoUT(ut, pl, flags)
+	s.release()
+	return attr, err
+}
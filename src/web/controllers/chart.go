@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go-swe/src/chart"
+	"go-swe/src/swe"
+	"gopkg.in/go-mixed/go-common.v1/web.v1/controllers"
+	"time"
+)
+
+type ChartController struct {
+	controllers.Controller
+}
+
+type natalRequest struct {
+	// 出生时间, 见 parseJulianDay, 不带时区时为UTC
+	Date      string  `json:"date" binding:"required"`
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+	// 宫位制的字母, 比如: "P" (Placidus), 默认为 "P"
+	HouseSystem string `json:"house_system"`
+	// 恒星黄道的岁差模式, 为空时为回归黄道
+	Ayanamsa *int32 `json:"ayanamsa"`
+	// 天体的id, 为空时使用 chart.DefaultBodies
+	Bodies []int `json:"bodies"`
+}
+
+func (c *ChartController) Natal() (gin.H, error) {
+	astronomy, _, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	var req natalRequest
+	if err := c.Context.ShouldBindJSON(&req); err != nil {
+		return nil, controllers.NewResponseException(4051, 400, err.Error())
+	}
+
+	jd, err := parseJulianDay(req.Date, time.UTC, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4053, 400, err.Error())
+	}
+
+	if len(req.HouseSystem) > 1 {
+		return nil, controllers.NewResponseException(4054, 400, fmt.Sprintf("invalid house system: %s", req.HouseSystem))
+	}
+
+	opts := &chart.NatalOptions{
+		JdUT:      jd,
+		Longitude: req.Longitude,
+		Latitude:  req.Latitude,
+	}
+	if req.HouseSystem != "" {
+		opts.HouseSystem = swe.HSys(req.HouseSystem[0])
+	}
+	if req.Ayanamsa != nil {
+		opts.Sidereal = true
+		opts.Ayanamsa = swe.Ayanamsa(*req.Ayanamsa)
+	}
+	for _, id := range req.Bodies {
+		opts.Bodies = append(opts.Bodies, swe.Planet(id))
+	}
+
+	natal, err := chart.NewChart(astronomy).Natal(opts)
+	if err != nil {
+		return nil, controllers.NewResponseException(4052, 400, err.Error())
+	}
+
+	return gin.H{
+		"date":       req.Date,
+		"at":         formatAt(jd, time.UTC, reform),
+		"longitude":  req.Longitude,
+		"latitude":   req.Latitude,
+		"result":     natal,
+		"sign_names": chart.SignStrings,
+	}, nil
+}
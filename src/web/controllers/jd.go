@@ -1,10 +1,12 @@
 package controllers
 
 import (
-	"github.com/araddon/dateparse"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"go-swe/src/astro"
+	"go-swe/src/swe"
 	"gopkg.in/go-mixed/go-common.v1/web.v1/controllers"
+	"strconv"
 	"time"
 )
 
@@ -12,26 +14,128 @@ type JDController struct {
 	controllers.Controller
 }
 
-type jdConvert struct {
-	Date string `uri:"date" binding:"required"`
+// formatDateTime 格式化swe返回的日期, 秒可能为60(闰秒), 所以不能使用 time.Time
+func formatDateTime(year, month, day, hour, minute int, second float64) string {
+	return fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%09.6fZ", year, month, day, hour, minute, second)
 }
 
-func (c *JDController) JdConvert(ctx *gin.Context) (gin.H, error) {
-
-	date := ctx.DefaultQuery("date", time.Now().Format(time.RFC3339))
-
-	if t, err := dateparse.ParseAny(date); err == nil {
-		jd := astro.TimeToJulianDay(t)
-		//deltaT := astronomy.DeltaT(jd)
-		jdEt := astro.NewEphemerisTime(jd)
-		return gin.H{
-			"jd":      jd,
-			"jd2000":  jd.ToJD2000(),
-			"delta_t": jdEt.DeltaT,
-			"jd_et":   jdEt.Value(),
-			"date":    date,
-		}, nil
-	} else {
+// formatJulianDay 儒略日格式化为日期, 不处理闰秒
+func formatJulianDay(jd float64, ct swe.CalType) string {
+	year, month, day, hours, _ := astronomy.Swe.RevJul(jd, ct)
+	hour, minute, second := astro.ExtractJulianDayHours(hours)
+	return formatDateTime(year, month, day, hour, minute, second)
+}
+
+// Convert 时间尺度的转换
+// 参数 date、jd、mjd、jd2000 任选其一, 后三者为UT1的儒略日; 都没有时为当前时间
+// 参数 date 支持天文纪年的 [-]YYYY-MM-DD, 不带时区时按照参数 tz 解析, 见 parseDate
+// 参数 delta_t 为ΔT的模型, 为空时使用swe的ΔT
+// 参数 reform 为改历, date 在改历之前时视为儒略历, 见 astro.GetCalendarReform
+func (c *JDController) Convert() (gin.H, error) {
+	flags := &swe.DateConvertFlags{Calendar: swe.Gregorian}
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	var year, month, day, hour, minute int
+	var second, jdTT, jdUT1 float64
+
+	jd, ok, err := c.julianDayQuery()
+	if err != nil {
 		return nil, controllers.NewResponseException(4001, 400, err.Error())
 	}
+
+	if ok {
+		jdUT1 = jd
+		if deltaT != "" {
+			flags.SetDeltaT(astronomy.DeltaT(astro.JulianDay(jdUT1)))
+		}
+		if year, month, day, hour, minute, second, err = astronomy.Swe.JdUT1ToUTC(jdUT1, flags); err != nil {
+			return nil, controllers.NewResponseException(4002, 400, err.Error())
+		}
+		if jdTT, _, err = astronomy.Swe.UTCToJD(year, month, day, hour, minute, second, flags); err != nil {
+			return nil, controllers.NewResponseException(4002, 400, err.Error())
+		}
+	} else {
+		date, err := parseDate(c.Context.DefaultQuery("date", time.Now().Format(time.RFC3339Nano)), parseTimezone(c.Context.Query("tz")), reform)
+		if err != nil {
+			return nil, controllers.NewResponseException(4001, 400, err.Error())
+		}
+		if deltaT != "" {
+			flags.SetDeltaT(astronomy.DeltaT(date.JulianDay()))
+		}
+		// 按照输入的当地日期选择历法, 儒略历的日期转为格里高利历, 之后都按照格里高利历计算
+		if date.Calendar == swe.Julian {
+			noon, _ := astronomy.Swe.JulDay(date.Year, date.Month, date.Day, 12, swe.Julian)
+			date.Year, date.Month, date.Day, _, _ = astronomy.Swe.RevJul(noon, swe.Gregorian)
+		}
+		// 转为UTC, 秒单独保留, 以支持闰秒
+		t := time.Date(date.Year, time.Month(date.Month), date.Day, date.Hour, date.Minute, 0, 0, time.FixedZone("", date.Offset)).UTC()
+		year, month, day, hour, minute = t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute()
+		second = date.Second
+
+		if jdTT, jdUT1, err = astronomy.Swe.UTCToJD(year, month, day, hour, minute, second, flags); err != nil {
+			return nil, controllers.NewResponseException(4002, 400, err.Error())
+		}
+	}
+
+	jdTAI := astronomy.FromTT(jdTT, astro.TAI)
+	jdTDB := astronomy.FromTT(jdTT, astro.TDB)
+	jdGPS := astronomy.FromTT(jdTT, astro.GPS)
+	// UTC的儒略日, 闰秒时与下一秒相同
+	jdUTC, _ := astronomy.Swe.JulDay(year, month, day, float64(hour)+float64(minute)/60+second/3600, swe.Gregorian)
+	utc := time.Date(year, time.Month(month), day, hour, minute, 0, 0, time.UTC).
+		Add(time.Duration(second * float64(time.Second)))
+
+	jdUT := astro.JulianDay(jdUT1)
+	return gin.H{
+		"utc":          formatDateTime(year, month, day, hour, minute, second),
+		"ut1":          formatJulianDay(jdUT1, swe.Gregorian),
+		"tt":           formatJulianDay(jdTT, swe.Gregorian),
+		"tai":          formatJulianDay(jdTAI, swe.Gregorian),
+		"tdb":          formatJulianDay(jdTDB, swe.Gregorian),
+		"gps":          formatJulianDay(jdGPS, swe.Gregorian),
+		"jd":           jdUT,
+		"mjd":          jdUT.ToMJD(),
+		"jd2000":       jdUT.ToJD2000(),
+		"jd_utc":       jdUTC,
+		"jd_tt":        jdTT,
+		"jd_tai":       jdTAI,
+		"jd_tdb":       jdTDB,
+		"jd_gps":       jdGPS,
+		"unix":         float64(utc.UnixNano()) / 1e9,
+		"delta_t":      (jdTT - jdUT1) * 86400,
+		"weekday":      utc.Weekday(),
+		"weekday_name": utc.Weekday().String(),
+		"julian_date":  formatJulianDay(jdUTC, swe.Julian),
+		// 按照改历选择历法的UTC日期
+		"calendar_date": formatAt(astro.JulianDay(jdUTC), time.UTC, reform),
+	}, nil
+}
+
+// julianDayQuery 读取 jd、mjd、jd2000 参数, 转为儒略日, 都没有时返回false
+func (c *JDController) julianDayQuery() (float64, bool, error) {
+	for _, key := range []string{"jd", "mjd", "jd2000"} {
+		value, ok := c.Context.GetQuery(key)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("%s: %w", key, err)
+		}
+		switch key {
+		case "mjd":
+			v = float64(astro.MJD(v).ToJulianDay())
+		case "jd2000":
+			v = float64(astro.JD2000(v).ToJulianDay())
+		}
+		return v, true, nil
+	}
+	return 0, false, nil
 }
@@ -7,6 +7,8 @@ import (
 	"gopkg.in/go-mixed/go-common.v1/cache.v1"
 	"gopkg.in/go-mixed/go-common.v1/utils/conv"
 	"gopkg.in/go-mixed/go-common.v1/web.v1/controllers"
+	"sort"
+	"strconv"
 	"time"
 )
 
@@ -15,9 +17,14 @@ type LunarController struct {
 }
 
 func (c *LunarController) PhasesByYear() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+
 	year := conv.Atoi(c.Context.Param("year"), 0)
 
-	if data, err := cache.Remember(fmt.Sprintf("lunar/phases/%d", year), cacheExpired, func() (interface{}, error) {
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("lunar/phases/%d", year)), cacheExpired, func() (interface{}, error) {
 		return astronomy.LunarPhases(year)
 	}); err == nil {
 		return gin.H{
@@ -30,14 +37,120 @@ func (c *LunarController) PhasesByYear() (gin.H, error) {
 	}
 }
 
+func (c *LunarController) PhasesByRange() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	tz := parseTimezone(c.Context.Query("tz"))
+	start := c.Context.DefaultQuery("start", time.Now().Format(time.RFC3339))
+	end := c.Context.DefaultQuery("end", time.Now().AddDate(1, 0, 0).Format(time.RFC3339))
+
+	startJd, endJd, err := parseJulianDayRange(start, end, tz, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4028, 400, err.Error())
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("lunar/phases/%f/%f", startJd, endJd)), cacheExpired, func() (interface{}, error) {
+		return astronomy.LunarPhasesRange(startJd, endJd)
+	}); err == nil {
+		type phase struct {
+			Index int             `json:"index"`
+			Name  string          `json:"name"`
+			JdUT  astro.JulianDay `json:"jd_ut"`
+			At    string          `json:"at"`
+		}
+
+		jds := append([]*astro.JulianDayExtra{}, data.([]*astro.JulianDayExtra)...)
+		sort.SliceStable(jds, func(i, j int) bool {
+			return jds[i].JdUT < jds[j].JdUT
+		})
+
+		var phases = []phase{}
+		for _, jd := range jds {
+			if jd.JdUT < startJd || jd.JdUT >= endJd {
+				continue
+			}
+			phases = append(phases, phase{
+				Index: jd.Index,
+				Name:  astro.LunarPhaseStrings[jd.Index],
+				JdUT:  jd.JdUT,
+				At:    formatAt(jd.JdUT, tz, reform),
+			})
+		}
+
+		return gin.H{
+			"start":    start,
+			"end":      end,
+			"start_jd": startJd,
+			"end_jd":   endJd,
+			"phases":   phases,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4029, 400, err.Error())
+	}
+}
+
+func (c *LunarController) Phase() (gin.H, error) {
+	astronomy, _, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	tz := parseTimezone(c.Context.Query("tz"))
+	date := c.Context.DefaultQuery("date", time.Now().Format(time.RFC3339))
+
+	jd, err := parseJulianDay(date, tz, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4026, 400, err.Error())
+	}
+
+	phase, err := astronomy.MoonPhaseAt(jd)
+	if err != nil {
+		return nil, controllers.NewResponseException(4027, 400, err.Error())
+	}
+
+	return gin.H{
+		"date":     date,
+		"jd_ut":    jd,
+		"new_moon": formatAt(phase.NewMoon, tz, reform),
+		"result":   phase,
+	}, nil
+}
+
 func (c *LunarController) MonthsByYear() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+	// 年初、年末按照改历计算
+	astronomy = astronomy.WithCalendarReform(reform)
+
 	year := conv.Atoi(c.Context.Param("year"), 0)
 
-	if data, err := cache.Remember(fmt.Sprintf("lunar/months/%d", year), cacheExpired, func() (interface{}, error) {
-		return astronomy.LunarMonths(year)
+	calendar, err := astro.GetLunarCalendar(c.Context.Query("calendar"))
+	if err != nil {
+		return nil, controllers.NewResponseException(4023, 400, err.Error())
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("lunar/months/%s/%s/%d", calendar.Name, reform.Name, year)), cacheExpired, func() (interface{}, error) {
+		return astronomy.LunarMonthsWithCalendar(year, calendar)
 	}); err == nil {
 		lunarMonths := data.([]*astro.LunarMonth)
-		tz, _ := time.LoadLocation("Asia/Shanghai")
+		tz := calendar.Location()
 
 		type lunarMonth struct {
 			JdUT astro.JulianDay `json:"jd_ut"`
@@ -46,11 +159,12 @@ func (c *LunarController) MonthsByYear() (gin.H, error) {
 			Leap bool            `json:"leap"`
 		}
 
+		// 闰月以"闰X月"为键, 不会覆盖同名的月
 		var _lunarMonths map[string]lunarMonth = map[string]lunarMonth{}
 		for _, month := range lunarMonths {
-			_lunarMonths[astro.LunarMonthStrings[month.Index]] = lunarMonth{
+			_lunarMonths[astro.GetLunarMonthString(month.Index, month.Leap)] = lunarMonth{
 				JdUT: month.JdUT,
-				At:   month.JdUT.ToTime(tz).Format(time.RFC3339),
+				At:   formatAt(month.JdUT, tz, reform),
 				Leap: month.Leap,
 				Days: month.Days,
 			}
@@ -58,9 +172,155 @@ func (c *LunarController) MonthsByYear() (gin.H, error) {
 
 		return gin.H{
 			"year":         year,
+			"calendar":     calendar,
 			"lunar_months": _lunarMonths,
 		}, nil
 	} else {
 		return nil, controllers.NewResponseException(4022, 400, err.Error())
 	}
 }
+
+func (c *LunarController) FestivalsByYear() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+	// 年初、年末按照改历计算
+	astronomy = astronomy.WithCalendarReform(reform)
+
+	year := conv.Atoi(c.Context.Param("year"), 0)
+
+	calendar, err := astro.GetLunarCalendar(c.Context.Query("calendar"))
+	if err != nil {
+		return nil, controllers.NewResponseException(4024, 400, err.Error())
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("lunar/festivals/%s/%s/%d", calendar.Name, reform.Name, year)), cacheExpired, func() (interface{}, error) {
+		return astronomy.FestivalsWithCalendar(year, calendar)
+	}); err == nil {
+		festivals := data.([]*astro.Festival)
+		tz := calendar.Location()
+
+		type festival struct {
+			Name string          `json:"name"`
+			JdUT astro.JulianDay `json:"jd_ut"`
+			Date string          `json:"date"`
+		}
+
+		var _festivals = make([]festival, 0, len(festivals))
+		for _, f := range festivals {
+			_festivals = append(_festivals, festival{
+				Name: f.Name,
+				JdUT: f.JdUT,
+				Date: f.JdUT.ToDate(tz, reform).DateString(),
+			})
+		}
+
+		return gin.H{
+			"year":      year,
+			"calendar":  calendar,
+			"festivals": _festivals,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4025, 400, err.Error())
+	}
+}
+
+// Orbit 时间范围内月亮的近地点、远地点、交点、赤纬极值, 以及带超级月亮、蓝月亮标记的月相
+// 参数 supermoon_margin 为超级月亮的阈值(千米), 见 astro.LunarPhasesDetailRange
+func (c *LunarController) Orbit() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	tz := parseTimezone(c.Context.Query("tz"))
+	start := c.Context.DefaultQuery("start", time.Now().Format(time.RFC3339))
+	end := c.Context.DefaultQuery("end", time.Now().AddDate(1, 0, 0).Format(time.RFC3339))
+
+	startJd, endJd, err := parseJulianDayRange(start, end, tz, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4061, 400, err.Error())
+	}
+	margin, err := strconv.ParseFloat(c.Context.DefaultQuery("supermoon_margin", "0"), 64)
+	if err != nil {
+		return nil, controllers.NewResponseException(4063, 400, fmt.Sprintf("invalid supermoon_margin: %s", err))
+	}
+	// 蓝月亮按照tz的公历月判断
+	_, offset := startJd.ToTime(tz).Zone()
+
+	type lunarOrbit struct {
+		events *astro.LunarOrbitEvents
+		phases []*astro.LunarPhaseDetail
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("lunar/orbit/%f/%f/%f/%d", startJd, endJd, margin, offset)), cacheExpired, func() (interface{}, error) {
+		events, phases, err := astronomy.LunarOrbit(startJd, endJd, margin, float64(offset)/86400.)
+		if err != nil {
+			return nil, err
+		}
+		return &lunarOrbit{events: events, phases: phases}, nil
+	}); err == nil {
+		orbit := data.(*lunarOrbit)
+
+		type apsis struct {
+			*astro.LunarApsis
+			Name string `json:"name"`
+			At   string `json:"at"`
+		}
+		type node struct {
+			*astro.LunarNode
+			At string `json:"at"`
+		}
+		type declination struct {
+			*astro.LunarDeclination
+			At string `json:"at"`
+		}
+		type phase struct {
+			*astro.LunarPhaseDetail
+			Name string `json:"name"`
+			At   string `json:"at"`
+		}
+
+		var apsides = []apsis{}
+		for _, a := range orbit.events.Apsides {
+			apsides = append(apsides, apsis{LunarApsis: a, Name: astro.LunarApsisStrings[a.Type], At: formatAt(a.JdUT, tz, reform)})
+		}
+		var nodes = []node{}
+		for _, n := range orbit.events.Nodes {
+			nodes = append(nodes, node{LunarNode: n, At: formatAt(n.JdUT, tz, reform)})
+		}
+		var declinations = []declination{}
+		for _, d := range orbit.events.Declinations {
+			declinations = append(declinations, declination{LunarDeclination: d, At: formatAt(d.JdUT, tz, reform)})
+		}
+		var phases = []phase{}
+		for _, p := range orbit.phases {
+			if p.JdUT >= endJd {
+				continue
+			}
+			phases = append(phases, phase{LunarPhaseDetail: p, Name: astro.LunarPhaseStrings[p.Index], At: formatAt(p.JdUT, tz, reform)})
+		}
+
+		return gin.H{
+			"start":        start,
+			"end":          end,
+			"start_jd":     startJd,
+			"end_jd":       endJd,
+			"apsides":      apsides,
+			"nodes":        nodes,
+			"declinations": declinations,
+			"phases":       phases,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4062, 400, err.Error())
+	}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/araddon/dateparse"
+	"github.com/gin-gonic/gin"
+	"go-swe/src/astro"
+	"go-swe/src/swe"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 范围查询的最大跨度 单位是 天
+const maxRangeDays = 3660
+
+// julianDayPrefix 儒略日参数的前缀, 比如: jd:2451545.0
+const julianDayPrefix = "jd:"
+
+// parseTimezone 解析tz参数, 无效时为UTC
+func parseTimezone(timezone string) *time.Location {
+	if tz, err := time.LoadLocation(timezone); err == nil {
+		return tz
+	}
+	return time.UTC
+}
+
+// parseJulianDay 将参数解析为儒略日
+// 以 jd: 开头的为儒略日(UT), 比如: jd:2451545.0
+// 否则为日期, 见 parseDate, 改历之前的日期视为儒略历
+func parseJulianDay(value string, tz *time.Location, reform *astro.CalendarReform) (astro.JulianDay, error) {
+	if strings.HasPrefix(value, julianDayPrefix) {
+		jd, err := strconv.ParseFloat(strings.TrimPrefix(value, julianDayPrefix), 64)
+		if err != nil {
+			return 0, err
+		}
+		return astro.JulianDay(jd), nil
+	}
+
+	date, err := parseDate(value, tz, reform)
+	if err != nil {
+		return 0, err
+	}
+	return date.JulianDay(), nil
+}
+
+// astronomicalDatePattern 天文纪年的日期, 年份可以为0或负数, 比如: -0043-03-15、-0043-03-15T12:00:00+01:00
+var astronomicalDatePattern = regexp.MustCompile(`^(-?\d{1,6})-(\d{2})-(\d{2})(?:[T ](\d{2}):(\d{2})(?::(\d{2}(?:\.\d+)?))?)?(Z|[+-]\d{2}:?\d{2})?$`)
+
+// parseDate 将参数解析为历法日期, 年月日时分秒为用户输入的值, 按照输入的日期选择历法
+// 先尝试 [-]YYYY-MM-DD[Thh:mm[:ss]][Z|±hh:mm], 支持天文纪年的0年及负数年份, 否则为dateparse支持的日期格式
+// 不带时区的日期按照tz解析
+func parseDate(value string, tz *time.Location, reform *astro.CalendarReform) (*astro.CalendarDate, error) {
+	date := &astro.CalendarDate{}
+	if m := astronomicalDatePattern.FindStringSubmatch(value); m != nil {
+		date.Year, _ = strconv.Atoi(m[1])
+		date.Month, _ = strconv.Atoi(m[2])
+		date.Day, _ = strconv.Atoi(m[3])
+		date.Hour, _ = strconv.Atoi(m[4])
+		date.Minute, _ = strconv.Atoi(m[5])
+		date.Second, _ = strconv.ParseFloat(m[6], 64)
+		if date.Month < 1 || date.Month > 12 || date.Day < 1 || date.Day > 31 || date.Hour > 23 || date.Minute > 59 || date.Second >= 61 {
+			return nil, fmt.Errorf("invalid date: %s", value)
+		}
+
+		loc := tz
+		if zone := m[7]; zone == "Z" {
+			loc = time.UTC
+		} else if zone != "" {
+			zone = strings.Replace(zone, ":", "", 1)
+			hours, _ := strconv.Atoi(zone[1:3])
+			minutes, _ := strconv.Atoi(zone[3:])
+			offset := hours*3600 + minutes*60
+			if zone[0] == '-' {
+				offset = -offset
+			}
+			loc = time.FixedZone("", offset)
+		}
+		// 只用于得到时区在该时刻的偏移
+		_, date.Offset = time.Date(date.Year, time.Month(date.Month), date.Day, date.Hour, date.Minute, 0, 0, loc).Zone()
+	} else {
+		t, err := dateparse.ParseIn(value, tz)
+		if err != nil {
+			return nil, err
+		}
+		date.Year, date.Month, date.Day = t.Year(), int(t.Month()), t.Day()
+		date.Hour, date.Minute = t.Hour(), t.Minute()
+		date.Second = float64(t.Second()) + float64(t.Nanosecond())/1e9
+		_, date.Offset = t.Zone()
+	}
+	date.Calendar = reform.CalTypeOfDate(date.Year, date.Month, date.Day)
+	return date, nil
+}
+
+// parsePlanetId 解析天体的id, 必须为整数, 见 swe.Planet
+func parsePlanetId(id string) (swe.Planet, error) {
+	planetId, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid planet id: %s", id)
+	}
+	return swe.Planet(planetId), nil
+}
+
+// parseJulianDayRange 解析start、end参数, 并检查跨度
+func parseJulianDayRange(start, end string, tz *time.Location, reform *astro.CalendarReform) (astro.JulianDay, astro.JulianDay, error) {
+	startJd, err := parseJulianDay(start, tz, reform)
+	if err != nil {
+		return 0, 0, fmt.Errorf("start: %w", err)
+	}
+	endJd, err := parseJulianDay(end, tz, reform)
+	if err != nil {
+		return 0, 0, fmt.Errorf("end: %w", err)
+	}
+
+	if endJd <= startJd || float64(endJd-startJd) > maxRangeDays {
+		return 0, 0, fmt.Errorf("end must be after start, and within %d days", maxRangeDays)
+	}
+	return startJd, endJd, nil
+}
+
+// parseCalendarReform 解析reform参数, 即儒略历改为格里高利历的改历, 见 astro.GetCalendarReform
+// 为空时为 astro.DefaultCalendarReform
+func parseCalendarReform(ctx *gin.Context) (*astro.CalendarReform, error) {
+	return astro.GetCalendarReform(ctx.Query("reform"))
+}
+
+// formatAt 儒略日格式化为tz时区的时间, 改历之前为儒略历, 年份为天文纪年
+// 改历之后与 time.RFC3339 的格式相同
+func formatAt(jd astro.JulianDay, tz *time.Location, reform *astro.CalendarReform) string {
+	return jd.ToDate(tz, reform).String()
+}
+
+// requestAstronomy 按照请求的 delta_t 参数选择ΔT模型, 见 astro.GetDeltaTModel
+// 参数为空时返回全局的 astronomy, 以及空的模型名称
+func requestAstronomy(ctx *gin.Context) (*astro.Astronomy, string, error) {
+	name := ctx.Query("delta_t")
+	if name == "" {
+		return astronomy, "", nil
+	}
+
+	model, err := astro.GetDeltaTModel(name)
+	if err != nil {
+		return nil, "", err
+	}
+	return astronomy.WithDeltaTModel(model), name, nil
+}
+
+// cacheKey 缓存的键, 非默认的ΔT模型需要单独缓存
+func cacheKey(deltaTModel, key string) string {
+	if deltaTModel == "" {
+		return key
+	}
+	return key + "@" + deltaTModel
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go-swe/src/astro"
+	"gopkg.in/go-mixed/go-common.v1/cache.v1"
+	"gopkg.in/go-mixed/go-common.v1/web.v1/controllers"
+	"time"
+)
+
+type PlanetController struct {
+	controllers.Controller
+}
+
+func (c *PlanetController) Phenomena() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	planetId, err := parsePlanetId(c.Context.Param("id"))
+	if err != nil {
+		return nil, controllers.NewResponseException(4031, 400, err.Error())
+	}
+	tz := parseTimezone(c.Context.Query("tz"))
+	date := c.Context.DefaultQuery("date", time.Now().Format(time.RFC3339))
+
+	jd, err := parseJulianDay(date, tz, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4035, 400, err.Error())
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("planets/%d/phenomena/%f", planetId, jd)), cacheExpired, func() (interface{}, error) {
+		return astronomy.PlanetPhenomena(planetId, astronomy.NewEphemerisTime(jd))
+	}); err == nil {
+		name, _ := astronomy.Swe.PlanetName(planetId)
+		return gin.H{
+			"planet_id": planetId,
+			"name":      name,
+			"date":      date,
+			"jd_ut":     jd,
+			"result":    data,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4032, 400, err.Error())
+	}
+}
+
+func (c *PlanetController) Events() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	planetId, err := parsePlanetId(c.Context.Param("id"))
+	if err != nil {
+		return nil, controllers.NewResponseException(4033, 400, err.Error())
+	}
+	tz := parseTimezone(c.Context.Query("tz"))
+	start := c.Context.DefaultQuery("start", time.Now().Format(time.RFC3339))
+	end := c.Context.DefaultQuery("end", time.Now().AddDate(1, 0, 0).Format(time.RFC3339))
+
+	startJd, endJd, err := parseJulianDayRange(start, end, tz, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4036, 400, err.Error())
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("planets/%d/events/%f/%f", planetId, startJd, endJd)), cacheExpired, func() (interface{}, error) {
+		return astronomy.PlanetEvents(planetId, startJd, endJd)
+	}); err == nil {
+		type event struct {
+			*astro.PlanetEvent
+			Name string `json:"name"`
+			At   string `json:"at"`
+		}
+
+		var events = []event{}
+		for _, e := range data.([]*astro.PlanetEvent) {
+			events = append(events, event{
+				PlanetEvent: e,
+				Name:        astro.PlanetEventStrings[e.Type],
+				At:          formatAt(e.JdUT, tz, reform),
+			})
+		}
+
+		name, _ := astronomy.Swe.PlanetName(planetId)
+		return gin.H{
+			"planet_id": planetId,
+			"name":      name,
+			"start":     start,
+			"end":       end,
+			"start_jd":  startJd,
+			"end_jd":    endJd,
+			"result":    events,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4034, 400, err.Error())
+	}
+}
@@ -15,16 +15,21 @@ type SolarController struct {
 }
 
 func (c *SolarController) TermsByYear() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+	// 年初、年末按照改历计算
+	astronomy = astronomy.WithCalendarReform(reform)
 
 	year := conv.Atoi(c.Context.Param("year"), 0)
-	timezone := c.Context.Query("tz")
-	var err error
-	var tz *time.Location
-	if tz, err = time.LoadLocation(timezone); err != nil {
-		tz = time.UTC
-	}
+	tz := parseTimezone(c.Context.Query("tz"))
 
-	if data, err := cache.Remember(fmt.Sprintf("solar/terms/%d", year), cacheExpired, func() (interface{}, error) {
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("solar/terms/%s/%d", reform.Name, year)), cacheExpired, func() (interface{}, error) {
 		return astronomy.SolarTerms(year)
 	}); err == nil {
 		jds := data.([]*astro.JulianDayExtra)
@@ -37,7 +42,7 @@ func (c *SolarController) TermsByYear() (gin.H, error) {
 			terms[astro.SolarTermsString[jd.Index]] =
 				term{
 					JdUT: jd.JdUT,
-					At:   jd.JdUT.ToTime(tz).Format(time.RFC3339),
+					At:   formatAt(jd.JdUT, tz, reform),
 				}
 		}
 
@@ -50,19 +55,145 @@ func (c *SolarController) TermsByYear() (gin.H, error) {
 	}
 }
 
-//func (c *SolarController) TermsByRange() (gin.H, *ResponseException)  {
-//	start := c.ctx.DefaultQuery("start", "")
-//	end := c.ctx.DefaultQuery("end", "")
-//
-//	if res, err := astronomy.SolarTermsRange(astro.JulianDay(start), astro.JulianDay(end)); err == nil {
-//		return gin.H{
-//			"start": start,
-//			"end": end,
-//			"start_jd": start_jd,
-//			"end_jd": end_jd,
-//			"result": res,
-//		}, nil
-//	} else {
-//		return nil, NewResponseException(4012, err.Error())
-//	}
-//}
+func (c *SolarController) TermsByRange() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+
+	tz := parseTimezone(c.Context.Query("tz"))
+	start := c.Context.DefaultQuery("start", time.Now().Format(time.RFC3339))
+	end := c.Context.DefaultQuery("end", time.Now().AddDate(1, 0, 0).Format(time.RFC3339))
+
+	startJd, endJd, err := parseJulianDayRange(start, end, tz, reform)
+	if err != nil {
+		return nil, controllers.NewResponseException(4012, 400, err.Error())
+	}
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("solar/terms/%f/%f", startJd, endJd)), cacheExpired, func() (interface{}, error) {
+		return astronomy.SolarTermsRange(startJd, endJd)
+	}); err == nil {
+		type term struct {
+			Index int             `json:"index"`
+			Name  string          `json:"name"`
+			JdUT  astro.JulianDay `json:"jd_ut"`
+			At    string          `json:"at"`
+		}
+
+		var terms = []term{}
+		for _, jd := range data.([]*astro.JulianDayExtra) {
+			if jd.JdUT < startJd || jd.JdUT >= endJd {
+				continue
+			}
+			terms = append(terms, term{
+				Index: jd.Index,
+				Name:  astro.SolarTermsString[jd.Index],
+				JdUT:  jd.JdUT,
+				At:    formatAt(jd.JdUT, tz, reform),
+			})
+		}
+
+		return gin.H{
+			"start":       start,
+			"end":         end,
+			"start_jd":    startJd,
+			"end_jd":      endJd,
+			"solar_terms": terms,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4016, 400, err.Error())
+	}
+}
+
+func (c *SolarController) DogDays() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+
+	year := conv.Atoi(c.Context.Param("year"), 0)
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("solar/dogdays/%d", year)), cacheExpired, func() (interface{}, error) {
+		return astronomy.DogDays(year)
+	}); err == nil {
+		return gin.H{
+			"year":   year,
+			"result": data,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4013, 400, err.Error())
+	}
+}
+
+func (c *SolarController) Winter9Days() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+
+	year := conv.Atoi(c.Context.Param("year"), 0)
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("solar/winter9/%d", year)), cacheExpired, func() (interface{}, error) {
+		return astronomy.Winter9Days(year)
+	}); err == nil {
+		return gin.H{
+			"year":   year,
+			"result": data,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4014, 400, err.Error())
+	}
+}
+
+func (c *SolarController) Seasons() (gin.H, error) {
+	astronomy, deltaT, err := requestAstronomy(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4003, 400, err.Error())
+	}
+	reform, err := parseCalendarReform(c.Context)
+	if err != nil {
+		return nil, controllers.NewResponseException(4004, 400, err.Error())
+	}
+	// 年初、年末按照改历计算
+	astronomy = astronomy.WithCalendarReform(reform)
+
+	year := conv.Atoi(c.Context.Param("year"), 0)
+	tz := parseTimezone(c.Context.Query("tz"))
+
+	if data, err := cache.Remember(cacheKey(deltaT, fmt.Sprintf("solar/seasons/%s/%d", reform.Name, year)), cacheExpired, func() (interface{}, error) {
+		return astronomy.Seasons(year)
+	}); err == nil {
+		seasons := data.(*astro.Seasons)
+
+		type event struct {
+			*astro.SeasonEvent
+			At string `json:"at"`
+		}
+		type apsis struct {
+			*astro.EarthApsis
+			At string `json:"at"`
+		}
+
+		var events = []event{}
+		for _, e := range seasons.Events {
+			events = append(events, event{SeasonEvent: e, At: formatAt(e.JdUT, tz, reform)})
+		}
+		var apsides = []apsis{}
+		for _, a := range seasons.Apsides {
+			apsides = append(apsides, apsis{EarthApsis: a, At: formatAt(a.JdUT, tz, reform)})
+		}
+
+		return gin.H{
+			"year":    year,
+			"events":  events,
+			"seasons": seasons.Seasons,
+			"apsides": apsides,
+		}, nil
+	} else {
+		return nil, controllers.NewResponseException(4015, 400, err.Error())
+	}
+}
@@ -19,10 +19,21 @@ func RegisterRouter(r *gin.Engine) {
 	r.GET("/solar/terms/:year", controllers.ControllerHandler("SolarController", "TermsByYear"))
 
 	r.GET("/solar/terms", controllers.ControllerHandler("SolarController", "TermsByRange"))
+	r.GET("/solar/dogdays/:year", controllers.ControllerHandler("SolarController", "DogDays"))
+	r.GET("/solar/winter9/:year", controllers.ControllerHandler("SolarController", "Winter9Days"))
+	r.GET("/solar/seasons/:year", controllers.ControllerHandler("SolarController", "Seasons"))
 
-	r.GET("/lunar/phases/", controllers.ControllerHandler("LunarController", "PhasesByRange"))
+	r.GET("/lunar/phases", controllers.ControllerHandler("LunarController", "PhasesByRange"))
+	r.GET("/lunar/phase", controllers.ControllerHandler("LunarController", "Phase"))
+	r.GET("/lunar/orbit", controllers.ControllerHandler("LunarController", "Orbit"))
 	r.GET("/lunar/phases/:year", controllers.ControllerHandler("LunarController", "PhasesByYear"))
 	r.GET("/lunar/months/:year", controllers.ControllerHandler("LunarController", "MonthsByYear"))
+	r.GET("/lunar/festivals/:year", controllers.ControllerHandler("LunarController", "FestivalsByYear"))
+
+	r.GET("/planets/:id/phenomena", controllers.ControllerHandler("PlanetController", "Phenomena"))
+	r.GET("/planets/:id/events", controllers.ControllerHandler("PlanetController", "Events"))
+
+	r.POST("/chart/natal", controllers.ControllerHandler("ChartController", "Natal"))
 }
 
 func RegisterControllers() {
@@ -37,4 +48,12 @@ func RegisterControllers() {
 	controllers.RegisterController("LunarController", func(ctx *gin.Context) controllers.IController {
 		return &innerControllers.LunarController{Controller: controllers.Controller{Context: ctx}}
 	})
+
+	controllers.RegisterController("PlanetController", func(ctx *gin.Context) controllers.IController {
+		return &innerControllers.PlanetController{Controller: controllers.Controller{Context: ctx}}
+	})
+
+	controllers.RegisterController("ChartController", func(ctx *gin.Context) controllers.IController {
+		return &innerControllers.ChartController{Controller: controllers.Controller{Context: ctx}}
+	})
 }